@@ -0,0 +1,71 @@
+package table
+
+import "math"
+
+// bloomFilter 布隆过滤器，用于在查找前快速排除一定不存在的键
+// 删除键时不会清除对应的位，只会增加误判，不会漏判；调整容量时重建
+type bloomFilter struct {
+	bits []uint64
+	// 位的数量
+	m uint64
+	// 每个键设置的位数
+	k int
+}
+
+// newBloomFilter 创建可容纳 n 个键、误判率约为 fpRate 的布隆过滤器
+func newBloomFilter(n int, fpRate float64) *bloomFilter {
+	m := uint64(math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &bloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// add 记录哈希值为 h 的键
+func (b *bloomFilter) add(h uint64) {
+	// 双重哈希，由一个哈希值派生出 k 个位置
+	h2 := mix64(h) | 1
+	for i := 0; i < b.k; i++ {
+		pos := (h + uint64(i)*h2) % b.m
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+// mayContain 判断哈希值为 h 的键是否可能存在，返回 false 时一定不存在
+func (b *bloomFilter) mayContain(h uint64) bool {
+	h2 := mix64(h) | 1
+	for i := 0; i < b.k; i++ {
+		pos := (h + uint64(i)*h2) % b.m
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// rebuildBloom 按当前的键重建布隆过滤器，清除已删除键留下的位
+// 表扩容超过预期数量后按负载因子允许的数量重新估算大小，保持误判率
+func (st *Table) rebuildBloom() {
+	if st.bloom == nil {
+		return
+	}
+
+	n := st.bloomN
+	if limit := int(float64(st.capacity) * st.loadFactor); limit > n {
+		n = limit
+	}
+	st.bloom = newBloomFilter(n, st.bloomFP)
+	for i := 0; i < st.capacity; i++ {
+		if st.metas[i]&0x03 == metaFull {
+			st.bloom.add(st.hash(st.entries[i].key))
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package table
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestBloomFilterNoFalseNegative 测试开启布隆过滤器后已存在的键总能找到
+func TestBloomFilterNoFalseNegative(t *testing.T) {
+	table := NewTable(8, WithBloomFilter(100, 0.01))
+
+	// 插入的数量远超预期，期间多次扩容重建过滤器
+	for i := 0; i < 5000; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	for i := 0; i < 5000; i += 2 {
+		table.Delete(fmt.Sprintf("key-%d", i))
+	}
+	table.Shrink()
+
+	for i := 0; i < 5000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		if i%2 == 1 && table.Find(key) != i {
+			t.Fatalf("已存在的 %s 未找到", key)
+		}
+		if i%2 == 0 && table.Contains(key) {
+			t.Fatalf("已删除的 %s 不应找到", key)
+		}
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+
+	// 过滤器应排除绝大多数不存在的键
+	filtered := 0
+	for i := 0; i < 1000; i++ {
+		if !table.bloom.mayContain(table.hash(fmt.Sprintf("miss-%d", i))) {
+			filtered++
+		}
+	}
+	if filtered < 900 {
+		t.Errorf("布隆过滤器只排除了 %d/1000 个不存在的键", filtered)
+	}
+}
+
+// TestBloomFilterDerived 测试派生的表同样开启布隆过滤器
+func TestBloomFilterDerived(t *testing.T) {
+	table := NewTable(8, WithBloomFilter(64, 0.01))
+	for i := 0; i < 20; i++ {
+		table.Insert(i, i)
+	}
+
+	filtered := table.Filter(func(key, value any) bool { return key.(int)%2 == 0 })
+	if filtered.bloom == nil {
+		t.Fatalf("Filter 得到的表应开启布隆过滤器")
+	}
+	for i := 0; i < 20; i++ {
+		if filtered.Contains(i) != (i%2 == 0) {
+			t.Errorf("Filter 得到的表 key=%d 查找错误", i)
+		}
+	}
+}
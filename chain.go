@@ -0,0 +1,92 @@
+package table
+
+import "fmt"
+
+// 拉链法复用 entries 作为条目池：每个桶通过 heads 指向池中的第一个条目，
+// 同一个桶中的条目以及空闲的条目都通过 side.next 串成单链表。
+// 删除时条目直接回到空闲链表，因此不会产生删除标记；
+// 其它按槽位遍历 entries 的方法不需要区分两种模式。
+
+// initChains 为新分配的 entries 初始化空桶，并把所有条目放入空闲链表
+func (st *Table) initChains() {
+	st.heads = make([]int, st.capacity)
+	st.side.next = make([]int, st.capacity)
+	for i := range st.entries {
+		st.side.next[i] = i + 2
+	}
+	st.side.next[st.capacity-1] = 0
+	st.free = 1
+}
+
+// chainSlot 在哈希值 h 对应的桶中查找 key，同时返回查找时经过的条目数
+// 插入模式下找不到时从空闲链表取出一个条目链入桶头部并返回
+func (st *Table) chainSlot(h uint64, key any, insertMode bool) (slot, distance int) {
+	bucket := st.indexOf(h)
+	want := metaFull | tagOf(h)
+	for i := st.heads[bucket]; i != 0; i = st.side.next[i-1] {
+		if st.metas[i-1] == want && st.keysEqual(st.entries[i-1].key, key) {
+			return i - 1, distance
+		}
+		distance++
+	}
+	if !insertMode || st.free == 0 {
+		return -1, distance
+	}
+
+	slot = st.free - 1
+	st.free = st.side.next[slot]
+	st.side.next[slot] = st.heads[bucket]
+	st.heads[bucket] = slot + 1
+	return slot, distance
+}
+
+// unlinkChain 把条目从所在的桶中摘下并放回空闲链表
+func (st *Table) unlinkChain(slot int) {
+	link := &st.heads[st.getIndex(st.entries[slot].key)]
+	for *link != slot+1 {
+		link = &st.side.next[*link-1]
+	}
+	*link = st.side.next[slot]
+	st.side.next[slot] = st.free
+	st.free = slot + 1
+}
+
+// validateChains 检查所有桶与空闲链表恰好覆盖每个条目一次
+func (st *Table) validateChains() error {
+	if len(st.heads) != st.capacity {
+		return fmt.Errorf("heads length %d not match capacity %d", len(st.heads), st.capacity)
+	}
+
+	seen := make([]bool, st.capacity)
+	visit := func(i int, full bool) error {
+		if i < 1 || i > st.capacity || seen[i-1] {
+			return fmt.Errorf("chain link %d is out of range or forms a cycle", i)
+		}
+		seen[i-1] = true
+		if (st.metas[i-1]&0x03 == metaFull) != full {
+			return fmt.Errorf("slot %d has meta %d in the wrong chain", i-1, st.metas[i-1])
+		}
+		return nil
+	}
+	for b := range st.heads {
+		for i := st.heads[b]; i != 0; i = st.side.next[i-1] {
+			if err := visit(i, true); err != nil {
+				return err
+			}
+			if idx := st.getIndex(st.entries[i-1].key); idx != b {
+				return fmt.Errorf("slot %d with key %v is in bucket %d, want %d", i-1, st.entries[i-1].key, b, idx)
+			}
+		}
+	}
+	for i := st.free; i != 0; i = st.side.next[i-1] {
+		if err := visit(i, false); err != nil {
+			return err
+		}
+	}
+	for i, ok := range seen {
+		if !ok {
+			return fmt.Errorf("slot %d is in neither a bucket nor the free list", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestChainingBasic 在拉链法下测试插入、查找、更新与删除
+func TestChainingBasic(t *testing.T) {
+	table := NewTable(8, WithChaining())
+
+	table.Insert("apple", 1)
+	table.Insert("banana", 2)
+	table.Insert(nil, "nil key")
+	if table.Find("apple") != 1 || table.Find("banana") != 2 || table.Find(nil) != "nil key" {
+		t.Errorf("插入后查找失败")
+	}
+	if table.Find("cherry") != nil || table.Contains("cherry") {
+		t.Errorf("不存在的键不应找到")
+	}
+
+	table.Insert("apple", 100)
+	if table.Find("apple") != 100 || table.Size() != 3 {
+		t.Errorf("更新后期望 apple=100, size=3, 实际为 %v, %d", table.Find("apple"), table.Size())
+	}
+
+	if !table.Delete("apple") || table.Delete("apple") {
+		t.Errorf("删除结果错误")
+	}
+	if table.Contains("apple") || table.Size() != 2 {
+		t.Errorf("删除后不应找到 apple, size 期望为 2, 实际为 %d", table.Size())
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+// TestChainingConflict 在拉链法下测试所有键哈希到同一个桶
+func TestChainingConflict(t *testing.T) {
+	table := NewTable(16, WithChaining())
+	table.hashFn = func(any) uint64 { return 0 }
+
+	for i := 0; i < 10; i++ {
+		table.Insert(fmt.Sprintf("conflict-%d", i), i)
+	}
+	for i := 0; i < 10; i += 2 {
+		table.Delete(fmt.Sprintf("conflict-%d", i))
+	}
+	for i := 0; i < 10; i++ {
+		val := table.Find(fmt.Sprintf("conflict-%d", i))
+		if i%2 == 1 && val != i {
+			t.Errorf("冲突场景查找失败, conflict-%d, 返回=%v", i, val)
+		}
+		if i%2 == 0 && val != nil {
+			t.Errorf("已删除的 conflict-%d 不应找到, 返回=%v", i, val)
+		}
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+// TestChainingResize 在拉链法下测试大量插入、删除以及扩缩容
+func TestChainingResize(t *testing.T) {
+	skipUnderInvariantChecks(t)
+	table := NewTable(8, WithChaining(), WithInPlaceResize())
+	for i := 0; i < 10000; i++ {
+		table.Insert(i, i)
+	}
+	for i := 0; i < 10000; i += 2 {
+		table.Delete(i)
+	}
+	table.Shrink()
+
+	for i := 0; i < 10000; i++ {
+		if i%2 == 1 && table.Find(i) != i {
+			t.Fatalf("缩容后 key=%d 查找失败", i)
+		}
+		if i%2 == 0 && table.Contains(i) {
+			t.Fatalf("已删除的 key=%d 不应找到", i)
+		}
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+// TestChainingNoTombstone 测试拉链法删除后不留下删除标记
+func TestChainingNoTombstone(t *testing.T) {
+	table := NewTable(64, WithChaining())
+	for round := 0; round < 10; round++ {
+		for i := 0; i < 40; i++ {
+			table.Insert(i, round)
+		}
+		for i := 0; i < 40; i++ {
+			table.Delete(i)
+		}
+	}
+
+	if table.Capacity() != 64 || table.ResizeCount() != 0 {
+		t.Errorf("反复插入删除不应扩容, 容量为 %d", table.Capacity())
+	}
+	if s := table.String(); !strings.Contains(s, "deleted: 0") {
+		t.Errorf("拉链法不应产生删除标记, 实际为 %s", s)
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+// TestChainingSnapshot 测试拉链法下修改原表不影响快照
+func TestChainingSnapshot(t *testing.T) {
+	table := NewTable(16, WithChaining())
+	table.hashFn = func(key any) uint64 { return uint64(key.(int)) % 4 }
+	for i := 0; i < 8; i++ {
+		table.Insert(i, i)
+	}
+
+	snap := table.Snapshot()
+	for i := 0; i < 8; i += 2 {
+		table.Delete(i)
+	}
+	table.Insert(100, 100)
+
+	for i := 0; i < 8; i++ {
+		if snap.Find(i) != i {
+			t.Errorf("快照中 key=%d 查找失败", i)
+		}
+	}
+	if snap.Contains(100) {
+		t.Errorf("快照中不应出现之后插入的键")
+	}
+	for _, tb := range []*Table{table, snap} {
+		if err := tb.Validate(); err != nil {
+			t.Errorf("校验失败: %v", err)
+		}
+	}
+}
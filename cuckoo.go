@@ -0,0 +1,124 @@
+package table
+
+import "fmt"
+
+// 布谷鸟哈希把 entries 分成三段：[0, n1) 为第一张表，[n1, n1+n2) 为第二张表，其余为备用区。
+// 每个键只能存放在两张表中各自的一个位置，或者备用区中。
+// 插入时两个位置都被占用，就把其中一个键挤到它在另一张表中的位置，依次类推；
+// 挤动次数超过上限时把最后被挤出的键放入备用区，备用区已满时扩容。
+// 查找最多检查两个位置，只有备用区非空时才需要再扫描备用区。
+
+const (
+	// cuckooMinCapacity 布谷鸟哈希的最小容量，保证两张表和备用区都不为空
+	cuckooMinCapacity = 4
+	// cuckooMaxKicks 一次插入最多挤动的次数
+	cuckooMaxKicks = 32
+)
+
+// cuckooLayout 返回容量为 capacity 时两张表的大小，其余槽位为备用区
+func cuckooLayout(capacity int) (n1, n2 int) {
+	stash := max(1, capacity/16)
+	rest := capacity - stash
+	n1 = (rest + 1) / 2
+	return n1, rest - n1
+}
+
+// cuckooPositions 返回哈希值为 h 的键在两张表中的位置
+func (st *Table) cuckooPositions(h uint64, capacity int) (p1, p2 int) {
+	n1, n2 := cuckooLayout(capacity)
+	return int(h % uint64(n1)), n1 + int(mix64(h)%uint64(n2))
+}
+
+// inStash 判断槽位是否位于备用区
+func (st *Table) inStash(slot int) bool {
+	n1, n2 := cuckooLayout(st.capacity)
+	return slot >= n1+n2
+}
+
+// cuckooSlot 查找 key 所在的槽位，同时返回查找时检查过的位置数减 1
+// 插入模式下找不到时腾出一个空槽位返回，备用区已满无法腾出时返回 -1
+func (st *Table) cuckooSlot(h uint64, key any, insertMode bool) (slot, distance int) {
+	capacity := st.capacity
+	want := metaFull | tagOf(h)
+	p1, p2 := st.cuckooPositions(h, capacity)
+	if st.metas[p1] == want && st.keysEqual(st.entries[p1].key, key) {
+		return p1, 0
+	}
+	if st.metas[p2] == want && st.keysEqual(st.entries[p2].key, key) {
+		return p2, 1
+	}
+	n1, n2 := cuckooLayout(capacity)
+	if st.stashed > 0 {
+		for i := n1 + n2; i < capacity; i++ {
+			if st.metas[i] == want && st.keysEqual(st.entries[i].key, key) {
+				return i, 2 + i - n1 - n2
+			}
+		}
+	}
+	if !insertMode {
+		return -1, 2 + capacity - n1 - n2
+	}
+
+	if st.metas[p1]&0x03 == metaEmpty {
+		return p1, 0
+	}
+	if st.metas[p2]&0x03 == metaEmpty {
+		return p2, 1
+	}
+	stash := -1
+	for i := n1 + n2; i < capacity; i++ {
+		if st.metas[i]&0x03 == metaEmpty {
+			stash = i
+			break
+		}
+	}
+	if stash < 0 {
+		return -1, 0
+	}
+
+	// 腾出 p1：把原来的键挤到它的另一个位置，直到遇到空位置
+	// 被挤动的键暂存在 p1 中，每次与下一个位置交换；被挤回 p1 的键直接放入备用区，最后 p1 留给新键
+	cur := p1
+	for kick := 0; kick < cuckooMaxKicks; kick++ {
+		q1, q2 := st.cuckooPositions(st.hash(st.entries[p1].key), capacity)
+		alt := q1
+		if cur == q1 {
+			alt = q2
+		}
+		if alt == p1 {
+			break
+		}
+		if st.metas[alt]&0x03 == metaEmpty {
+			st.moveSlot(alt, p1)
+			return p1, 0
+		}
+		st.swapSlots(p1, alt)
+		cur = alt
+	}
+
+	st.moveSlot(stash, p1)
+	st.stashed++
+	return p1, 0
+}
+
+// validateCuckoo 检查每个键都位于它的两个位置之一或备用区，且备用区的计数正确
+func (st *Table) validateCuckoo() error {
+	n1, n2 := cuckooLayout(st.capacity)
+	stashed := 0
+	for i := 0; i < st.capacity; i++ {
+		if st.metas[i]&0x03 != metaFull {
+			continue
+		}
+		if i >= n1+n2 {
+			stashed++
+			continue
+		}
+		if p1, p2 := st.cuckooPositions(st.hash(st.entries[i].key), st.capacity); i != p1 && i != p2 {
+			return fmt.Errorf("slot %d with key %v is at neither of its positions %d and %d", i, st.entries[i].key, p1, p2)
+		}
+	}
+	if stashed != st.stashed {
+		return fmt.Errorf("stashed count %d not match stash entries %d", st.stashed, stashed)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package table
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestCuckooBasic 在布谷鸟哈希下测试插入、查找、更新与删除
+func TestCuckooBasic(t *testing.T) {
+	table := NewTable(8, WithCuckoo())
+	for i := 0; i < 1000; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	table.Insert("key-0", -1)
+	for i := 0; i < 1000; i += 2 {
+		if !table.Delete(fmt.Sprintf("key-%d", i)) {
+			t.Errorf("删除 key-%d 失败", i)
+		}
+	}
+
+	if table.Size() != 500 {
+		t.Errorf("期望 size=500, 实际为 %d", table.Size())
+	}
+	for i := 0; i < 1000; i++ {
+		v := table.Find(fmt.Sprintf("key-%d", i))
+		if i%2 == 1 && v != i {
+			t.Errorf("key-%d 查找失败, 返回 %v", i, v)
+		}
+		if i%2 == 0 && v != nil {
+			t.Errorf("已删除的 key-%d 不应找到, 返回 %v", i, v)
+		}
+	}
+
+	// 备用区为空时每个键最多检查两个位置
+	if table.stashed == 0 {
+		for i := 1; i < 1000; i += 2 {
+			if d, _ := table.ProbeDistance(fmt.Sprintf("key-%d", i)); d > 1 {
+				t.Errorf("key-%d 检查了 %d 个位置", i, d+1)
+			}
+		}
+	}
+	if s := table.String(); table.deleted != 0 {
+		t.Errorf("布谷鸟哈希不应产生删除标记, 实际为 %s", s)
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+// TestCuckooConflict 在布谷鸟哈希下测试所有键的哈希值相同
+// 每个键的两个位置都相同，多出的键放入备用区，备用区放满后扩容
+func TestCuckooConflict(t *testing.T) {
+	table := NewTable(8, WithCuckoo())
+	table.hashFn = func(any) uint64 { return 0 }
+
+	for i := 0; i < 10; i++ {
+		table.Insert(fmt.Sprintf("conflict-%d", i), i)
+	}
+	for i := 0; i < 10; i++ {
+		val := table.Find(fmt.Sprintf("conflict-%d", i))
+		if val != i {
+			t.Errorf("冲突场景查找失败, conflict-%d, 返回=%v", i, val)
+		}
+	}
+	if table.Size() != 10 || table.stashed != 8 {
+		t.Errorf("期望 size=10 且 8 个键在备用区, 实际为 %d, %d", table.Size(), table.stashed)
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+
+	table.Delete("conflict-9")
+	if table.Contains("conflict-9") || table.Size() != 9 {
+		t.Errorf("删除备用区中的键失败")
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+// TestCuckooSnapshot 测试布谷鸟哈希下挤动键不影响快照
+func TestCuckooSnapshot(t *testing.T) {
+	table := NewTable(8, WithCuckoo())
+	for i := 0; i < 50; i++ {
+		table.Insert(i, i)
+	}
+
+	snap := table.Snapshot()
+	for i := 50; i < 200; i++ {
+		table.Insert(i, i)
+	}
+	table.Delete(0)
+
+	for i := 0; i < 50; i++ {
+		if snap.Find(i) != i {
+			t.Errorf("快照中 key=%d 查找失败", i)
+		}
+	}
+	if snap.Contains(100) || snap.Size() != 50 {
+		t.Errorf("快照中不应出现之后插入的键")
+	}
+	for _, tb := range []*Table{table, snap} {
+		if err := tb.Validate(); err != nil {
+			t.Errorf("校验失败: %v", err)
+		}
+	}
+}
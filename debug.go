@@ -0,0 +1,201 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validate 检查表的内部结构是否完整，用于调试
+// 检查内容：每个已占用的槽位都能从其初始槽位沿探测序列找到（与自身不相等的键除外），
+// size 和删除标记的计数与实际数量一致，且每个槽位的元数据都是合法值，已占用槽位的标签与键的哈希值一致
+// 使用 -tags tabledebug 编译时，Insert、Delete 和扩缩容之后会自动调用，发现损坏立即 panic
+func (st *Table) Validate() error {
+	if len(st.entries) != st.capacity {
+		return fmt.Errorf("entries length %d not match capacity %d", len(st.entries), st.capacity)
+	}
+	if len(st.metas) != st.capacity {
+		return fmt.Errorf("metas length %d not match capacity %d", len(st.metas), st.capacity)
+	}
+
+	live, deleted := 0, 0
+	for i := 0; i < st.capacity; i++ {
+		switch st.metas[i] & 0x03 {
+		case metaEmpty:
+			continue
+		case metaDel:
+			deleted++
+			continue
+		case metaFull:
+		default:
+			return fmt.Errorf("slot %d has invalid meta %d", i, st.metas[i])
+		}
+
+		live++
+		if tag := tagOf(st.hash(st.entries[i].key)); st.metas[i]&^0x03 != tag {
+			return fmt.Errorf("slot %d with key %v has tag %#x, want %#x", i, st.entries[i].key, st.metas[i]&^0x03, tag)
+		}
+		// 与自身不相等的键（NaN、无法比较的键）按设计无法查找，只检查标签
+		if key := st.entries[i].key; !st.keysEqual(key, key) {
+			continue
+		}
+		if slot := st.lookupSlot(st.entries[i].key); slot != i {
+			return fmt.Errorf("slot %d with key %v is not reachable from its probe sequence (found at %d)", i, st.entries[i].key, slot)
+		}
+	}
+
+	if live != st.size {
+		return fmt.Errorf("size %d not match live entries %d", st.size, live)
+	}
+	if deleted != st.deleted {
+		return fmt.Errorf("deleted count %d not match tombstones %d", st.deleted, deleted)
+	}
+	if st.chaining {
+		return st.validateChains()
+	}
+	if st.cuckoo {
+		return st.validateCuckoo()
+	}
+	return nil
+}
+
+// liveCount 逐个扫描槽位统计已占用槽位的数量，用于检查 size 是否与实际数量一致
+func (st *Table) liveCount() int {
+	count := 0
+	for i := 0; i < st.capacity; i++ {
+		if st.metas[i]&0x03 == metaFull {
+			count++
+		}
+	}
+	return count
+}
+
+// ProbeDistance 返回查找 key 时探测的步数，键位于初始槽位时为 0
+// 键不存在时第二个返回值为 false，可用于找出冲突严重的键
+func (st *Table) ProbeDistance(key any) (int, bool) {
+	if st.lookup(key) < 0 {
+		return 0, false
+	}
+
+	_, distance := st.probeSlot(st.hash(key), key, false)
+	return distance, true
+}
+
+// EntryInfo 是 GetEntry 返回的条目副本，附带条目所在槽位的状态
+// 表中的槽位状态存放在 Table.metas，不占用每个条目的空间，只在副本中携带
+type EntryInfo struct {
+	Entry
+	meta byte
+}
+
+// Meta 返回条目所在槽位的状态：0 为空，1 为已占用，2 为已删除
+func (e EntryInfo) Meta() byte {
+	return e.meta & 0x03
+}
+
+// GetEntry 返回 key 所在条目的只读副本，包括槽位状态，用于检查内部状态
+// 键不存在时第二个返回值为 false；修改副本不会影响表
+func (st *Table) GetEntry(key any) (EntryInfo, bool) {
+	slot := st.lookup(key)
+	if slot < 0 {
+		return EntryInfo{}, false
+	}
+
+	e := EntryInfo{Entry: st.entries[slot], meta: st.metas[slot]}
+	e.value = st.valueAt(slot)
+	return e, true
+}
+
+// EntrySlots 按槽位顺序返回每个槽位的状态：0 为空，1 为已占用，2 为已删除，与 EntryInfo.Meta 一致
+// 可用于可视化聚集情况；返回的是副本，修改不会影响表
+func (st *Table) EntrySlots() []byte {
+	slots := make([]byte, st.capacity)
+	for i, meta := range st.metas {
+		slots[i] = meta & 0x03
+	}
+	return slots
+}
+
+// RangeSlots 按槽位从小到大的顺序遍历键值对，同时传入槽位索引，fn 返回 false 时停止
+// 与 Range 不同，不受 WithInsertionOrder 影响，可用于确定性的导出和观察聚集情况；跳过空槽位、删除标记和已过期的键
+func (st *Table) RangeSlots(fn func(slot int, key, value any) bool) {
+	now := st.expireNow()
+	for i := 0; i < st.capacity; i++ {
+		if st.live(i, now) && !fn(i, st.entries[i].key, st.valueAt(i)) {
+			return
+		}
+	}
+}
+
+// HashDistribution 统计 sampleKeys 在当前哈希函数和容量下各自的初始槽位，返回槽位到键数量的映射
+// 不要求键已存入表中，可以在插入前检查哈希函数对数据集是否分布均匀
+func (st *Table) HashDistribution(sampleKeys []any) map[int]int {
+	dist := make(map[int]int)
+	for _, key := range sampleKeys {
+		dist[st.getIndex(key)]++
+	}
+	return dist
+}
+
+// stringLimit String 最多列出的键值对数量
+const stringLimit = 20
+
+// String 返回表的可读描述，包含容量、数量、删除标记数量以及最多 20 个键值对
+func (st *Table) String() string {
+	deleted := 0
+	for i := 0; i < st.capacity; i++ {
+		if st.metas[i]&0x03 == metaDel {
+			deleted++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Table{capacity: %d, size: %d, deleted: %d, entries: [", st.capacity, st.size, deleted)
+	listed := 0
+	now := st.expireNow()
+	for i := 0; i < st.capacity; i++ {
+		if !st.live(i, now) {
+			continue
+		}
+		if listed == stringLimit {
+			b.WriteString(" ...")
+			break
+		}
+		if listed > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%v:%v", st.entries[i].key, st.valueAt(i))
+		listed++
+	}
+	b.WriteString("]}")
+	return b.String()
+}
+
+// metaName 返回元数据标记的名称
+func metaName(meta byte) string {
+	switch meta & 0x03 {
+	case metaEmpty:
+		return "EMPTY"
+	case metaFull:
+		return "FULL"
+	case metaDel:
+		return "DEL"
+	default:
+		return "INVALID"
+	}
+}
+
+// Dump 逐个槽位输出索引、状态和键，便于观察聚集和探测链
+// 每行格式为 "索引 状态 键"，非已占用槽位不输出键
+func (st *Table) Dump() string {
+	var b strings.Builder
+	width := len(fmt.Sprint(st.capacity - 1))
+	for i := 0; i < st.capacity; i++ {
+		meta := st.metas[i] & 0x03
+		fmt.Fprintf(&b, "%*d %s", width, i, metaName(meta))
+		if meta == metaFull {
+			fmt.Fprintf(&b, " %v", st.entries[i].key)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
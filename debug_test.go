@@ -0,0 +1,276 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestValidate 测试结构完整的表可以通过校验
+func TestValidate(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 100; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	for i := 0; i < 100; i += 3 {
+		table.Delete(fmt.Sprintf("key-%d", i))
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("正常的表校验失败: %v", err)
+	}
+}
+
+// TestValidateCorrupted 测试人为破坏内部结构后校验能发现问题
+func TestValidateCorrupted(t *testing.T) {
+	build := func() *Table {
+		table := NewTable(16)
+		table.hashFn = func(any) uint64 { return 0 }
+		for i := 0; i < 5; i++ {
+			table.Insert(i, i)
+		}
+		return table
+	}
+
+	// size 与实际数量不一致
+	table := build()
+	table.size++
+	if err := table.Validate(); err == nil {
+		t.Errorf("size 被破坏后校验应失败")
+	}
+
+	// 非法的元数据
+	table = build()
+	table.metas[2] = 3
+	if err := table.Validate(); err == nil {
+		t.Errorf("元数据被破坏后校验应失败")
+	}
+
+	// 探测链被截断, 后面的键无法被找到
+	table = build()
+	table.metas[1] = metaEmpty
+	table.entries[1].key = nil
+	table.size--
+	if err := table.Validate(); err == nil {
+		t.Errorf("探测链断裂后校验应失败")
+	}
+
+	// 重复的键
+	table = build()
+	table.entries[3].key = 1
+	if err := table.Validate(); err == nil {
+		t.Errorf("存在重复键时校验应失败")
+	}
+}
+
+type panicStringer struct{}
+
+func (panicStringer) String() string { panic("boom") }
+
+// TestProbeDistance 测试哈希全部冲突时探测步数依次递增
+func TestProbeDistance(t *testing.T) {
+	table := NewTable(32)
+	table.hashFn = func(any) uint64 { return 0 }
+
+	for i := 0; i < 10; i++ {
+		table.Insert(i, i)
+	}
+	for i := 0; i < 10; i++ {
+		d, ok := table.ProbeDistance(i)
+		if !ok || d != i {
+			t.Errorf("key=%d 期望探测步数 %d, 实际为 (%d, %v)", i, i, d, ok)
+		}
+	}
+
+	if d, ok := table.ProbeDistance(100); ok || d != 0 {
+		t.Errorf("不存在的键期望返回 (0, false), 实际为 (%d, %v)", d, ok)
+	}
+}
+
+// TestGetEntry 测试 GetEntry 返回已占用条目的副本
+func TestGetEntry(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("a", 1)
+
+	e, ok := table.GetEntry("a")
+	if !ok || e.Key() != "a" || e.Value() != 1 {
+		t.Fatalf("期望返回 (a, 1), 实际为 (%v, %v, %v)", e.Key(), e.Value(), ok)
+	}
+	if e.Meta() != metaFull {
+		t.Errorf("查找到的条目状态期望为 metaFull, 实际为 %d", e.Meta())
+	}
+
+	// 修改副本不影响表
+	e.value = 2
+	if table.Find("a") != 1 {
+		t.Errorf("修改副本不应影响表")
+	}
+
+	if e, ok := table.GetEntry("b"); ok || e.Meta() != metaEmpty {
+		t.Errorf("不存在的键期望返回空条目, 实际为 (%v, %v)", e, ok)
+	}
+}
+
+// TestString 测试 String 的输出
+func TestString(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("apple", 1)
+	table.Insert(nil, nil)
+	table.Insert("bad", panicStringer{})
+	table.Insert("tmp", 0)
+	table.Delete("tmp")
+
+	s := fmt.Sprintf("%v", table)
+	for _, want := range []string{"size: 3", "deleted: 1", "apple:1", "<nil>:<nil>"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("输出 %q 中应包含 %q", s, want)
+		}
+	}
+
+	// 超过上限时只列出部分并以省略号结尾
+	table = NewTable(8)
+	for i := 0; i < 100; i++ {
+		table.Insert(i, i)
+	}
+	s = table.String()
+	if !strings.HasSuffix(s, " ...]}") {
+		t.Errorf("超过上限时应以省略号结尾, 实际为 %q", s)
+	}
+	if n := strings.Count(s, ":") - 4; n != stringLimit { // 减去 4 个字段名中的冒号
+		t.Errorf("期望列出 %d 个键值对, 实际为 %d", stringLimit, n)
+	}
+}
+
+// TestDump 在恒定哈希的冲突场景下测试 Dump 输出
+func TestDump(t *testing.T) {
+	table := NewTable(16)
+	table.hashFn = func(any) uint64 { return 0 }
+	for i := 0; i < 6; i++ {
+		table.Insert(fmt.Sprintf("conflict-%d", i), i)
+	}
+	table.Delete("conflict-5")
+
+	dump := table.Dump()
+	lines := strings.Split(strings.TrimSuffix(dump, "\n"), "\n")
+	if len(lines) != 16 {
+		t.Fatalf("期望输出 16 行, 实际为 %d", len(lines))
+	}
+
+	// 所有冲突键应占据从 0 开始的连续槽位
+	for i := 0; i < 5; i++ {
+		want := fmt.Sprintf("%2d FULL conflict-%d", i, i)
+		if lines[i] != want {
+			t.Errorf("第 %d 行期望为 %q, 实际为 %q", i, want, lines[i])
+		}
+	}
+	if lines[5] != " 5 DEL" {
+		t.Errorf("第 5 行期望为删除标记, 实际为 %q", lines[5])
+	}
+	for i := 6; i < 16; i++ {
+		if !strings.HasSuffix(lines[i], "EMPTY") {
+			t.Errorf("第 %d 行期望为空槽位, 实际为 %q", i, lines[i])
+		}
+	}
+
+	if table.Dump() != dump {
+		t.Errorf("相同状态下 Dump 输出应一致")
+	}
+}
+
+// TestEntrySlots 测试恒定哈希下 EntrySlots 反映插入和删除的位置
+func TestEntrySlots(t *testing.T) {
+	table := NewTable(8)
+	table.hashFn = func(any) uint64 { return 2 }
+
+	for i := 0; i < 4; i++ {
+		table.Insert(i, i)
+	}
+	table.Delete(1)
+
+	slots := table.EntrySlots()
+	if len(slots) != table.Capacity() {
+		t.Fatalf("期望长度为 %d, 实际为 %d", table.Capacity(), len(slots))
+	}
+	expected := []byte{0, 0, 1, 2, 1, 1, 0, 0}
+	if fmt.Sprint(slots) != fmt.Sprint(expected) {
+		t.Errorf("期望槽位状态为 %v, 实际为 %v", expected, slots)
+	}
+
+	// 修改返回值不影响表
+	for i := range slots {
+		slots[i] = 0
+	}
+	if v := table.Find(3); v != 3 {
+		t.Errorf("修改返回值后查找失败, 返回 %v", v)
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+// TestHashDistribution 测试均匀的键分散到多个槽位, 恒定哈希下全部落在同一个槽位
+func TestHashDistribution(t *testing.T) {
+	keys := make([]any, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+
+	table := NewTable(1024)
+	dist := table.HashDistribution(keys)
+	total, most := 0, 0
+	for slot, n := range dist {
+		if slot < 0 || slot >= table.Capacity() {
+			t.Errorf("槽位 %d 越界", slot)
+		}
+		total += n
+		most = max(most, n)
+	}
+	if total != len(keys) {
+		t.Errorf("期望统计 %d 个键, 实际为 %d", len(keys), total)
+	}
+	// 1000 个键随机落入 1024 个槽位, 期望占用约 630 个
+	if len(dist) < 500 || most > 10 {
+		t.Errorf("均匀哈希期望分散到多个槽位, 实际占用 %d 个, 最多 %d 个", len(dist), most)
+	}
+	if table.Size() != 0 {
+		t.Errorf("HashDistribution 不应修改表")
+	}
+
+	table.hashFn = func(any) uint64 { return 0 }
+	if dist := table.HashDistribution(keys); len(dist) != 1 || dist[0] != len(keys) {
+		t.Errorf("恒定哈希期望全部落在槽位 0, 实际为 %v", dist)
+	}
+}
+
+// TestRangeSlots 测试按槽位升序遍历, 不受插入顺序影响
+func TestRangeSlots(t *testing.T) {
+	table := NewTable(16, WithInsertionOrder())
+	table.hashFn = func(key any) uint64 { return uint64(key.(int)) }
+	for _, k := range []int{9, 3, 12, 5, 1} {
+		table.Insert(k, k*10)
+	}
+	table.Delete(5)
+
+	var slots []int
+	table.RangeSlots(func(slot int, key, value any) bool {
+		if table.EntrySlots()[slot] != metaFull {
+			t.Errorf("槽位 %d 不是已占用状态", slot)
+		}
+		if value != key.(int)*10 {
+			t.Errorf("槽位 %d 的值错误, key=%v, value=%v", slot, key, value)
+		}
+		slots = append(slots, slot)
+		return true
+	})
+	if fmt.Sprint(slots) != "[1 3 9 12]" {
+		t.Errorf("期望按槽位升序遍历 [1 3 9 12], 实际为 %v", slots)
+	}
+
+	visited := 0
+	table.RangeSlots(func(int, any, any) bool {
+		visited++
+		return visited < 2
+	})
+	if visited != 2 {
+		t.Errorf("返回 false 后应停止, 实际遍历了 %d 个", visited)
+	}
+}
@@ -0,0 +1,56 @@
+package table
+
+// ResizeReason 表示调整容量事件的原因
+type ResizeReason int
+
+const (
+	// ResizeGrow 扩容，包括插入时自动扩容以及 Expand、Reserve 等
+	ResizeGrow ResizeReason = iota
+	// ResizeShrink 缩容
+	ResizeShrink
+	// ResizeCompact 原容量重建以清除删除标记
+	ResizeCompact
+)
+
+// String 返回原因的名称
+func (r ResizeReason) String() string {
+	switch r {
+	case ResizeGrow:
+		return "grow"
+	case ResizeShrink:
+		return "shrink"
+	case ResizeCompact:
+		return "compact"
+	}
+	return "unknown"
+}
+
+// ResizeEvent 是一次调整容量或整理的记录
+type ResizeEvent struct {
+	OldCap int
+	NewCap int
+	Reason ResizeReason
+}
+
+// resizeEventBuffer 事件通道的缓冲大小
+const resizeEventBuffer = 64
+
+// ResizeEvents 返回调整容量和整理事件的通道，用于监控，多次调用返回同一个通道
+// 发送不会阻塞：缓冲区满时丢弃新事件，不影响插入和删除；快照与派生的表不继承通道
+func (st *Table) ResizeEvents() <-chan ResizeEvent {
+	if st.resizeEvents == nil {
+		st.resizeEvents = make(chan ResizeEvent, resizeEventBuffer)
+	}
+	return st.resizeEvents
+}
+
+// emitResize 向事件通道发送事件，没有调用过 ResizeEvents 或缓冲区已满时直接返回
+func (st *Table) emitResize(oldCap, newCap int, reason ResizeReason) {
+	if st.resizeEvents == nil {
+		return
+	}
+	select {
+	case st.resizeEvents <- ResizeEvent{OldCap: oldCap, NewCap: newCap, Reason: reason}:
+	default:
+	}
+}
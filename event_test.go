@@ -0,0 +1,60 @@
+package table
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestResizeEvents 测试扩容、整理和缩容后事件通道收到对应的事件
+func TestResizeEvents(t *testing.T) {
+	table := NewTable(8)
+	events := table.ResizeEvents()
+	if table.ResizeEvents() != events {
+		t.Errorf("多次调用应返回同一个通道")
+	}
+
+	for i := 0; i < 7; i++ {
+		table.Insert(i, i)
+	}
+	select {
+	case e := <-events:
+		if e != (ResizeEvent{OldCap: 8, NewCap: 16, Reason: ResizeGrow}) {
+			t.Errorf("期望收到 8 -> 16 的扩容事件, 实际为 %+v", e)
+		}
+	default:
+		t.Fatalf("扩容后没有收到事件")
+	}
+
+	table.Delete(0)
+	table.Compact()
+	if e := <-events; e.Reason != ResizeCompact || e.OldCap != 16 || e.NewCap != 16 {
+		t.Errorf("期望收到整理事件, 实际为 %+v", e)
+	}
+
+	table.Shrink()
+	if e := <-events; e.Reason != ResizeShrink || e.NewCap >= e.OldCap {
+		t.Errorf("期望收到缩容事件, 实际为 %+v", e)
+	}
+	if s := fmt.Sprint(ResizeShrink); s != "shrink" {
+		t.Errorf("期望原因名称为 shrink, 实际为 %s", s)
+	}
+}
+
+// TestResizeEventsNonBlocking 测试无人接收时丢弃事件而不阻塞插入
+func TestResizeEventsNonBlocking(t *testing.T) {
+	skipUnderInvariantChecks(t)
+	table := NewTable(8, WithGrowthFactor(1.1))
+	events := table.ResizeEvents()
+	for i := 0; i < 100000; i++ {
+		table.Insert(i, i)
+	}
+	if table.ResizeCount() <= cap(events) {
+		t.Fatalf("扩容次数 %d 应超过缓冲区大小 %d", table.ResizeCount(), cap(events))
+	}
+	if len(events) != cap(events) {
+		t.Errorf("期望缓冲区已满, 实际有 %d 个事件", len(events))
+	}
+	if e := <-events; e.OldCap != 8 {
+		t.Errorf("缓冲区满后应丢弃新事件, 第一个事件为 %+v", e)
+	}
+}
@@ -0,0 +1,53 @@
+package table
+
+import "slices"
+
+// Freeze 把表冻结为只读，冻结后无法解冻
+//
+// 冻结后查找类方法照常工作；修改类方法中返回 error 的（如 InsertBatch、SetLoadFactor）返回 ErrFrozen，
+// 其余（如 Insert、Delete、Expand、Shrink）会以 ErrFrozen panic
+func (st *Table) Freeze() {
+	st.frozen = true
+}
+
+// Frozen 判断表是否已被冻结
+func (st *Table) Frozen() bool {
+	return st.frozen
+}
+
+// checkWritable 表已冻结时 panic
+func (st *Table) checkWritable() {
+	if st.frozen {
+		panic(ErrFrozen)
+	}
+}
+
+// Snapshot 返回当前表的只读快照
+//
+// 快照与当前表共享底层数组，创建快照的开销与表的大小无关；
+// 当前表在下一次修改时才复制底层数组，之后的修改不会影响快照。
+// 快照已被冻结，不会继承 OnInsert 等回调。
+func (st *Table) Snapshot() *Table {
+	snap := *st
+	snap.frozen = true
+	snap.onResize = nil
+	snap.resizeEvents = nil
+	snap.onInsert = nil
+	snap.onDelete = nil
+
+	st.shared = true
+	return &snap
+}
+
+// unshare 底层数组与快照共享时复制一份，之后的写入不再影响快照
+func (st *Table) unshare() {
+	if !st.shared {
+		return
+	}
+	st.entries = slices.Clone(st.entries)
+	st.metas = slices.Clone(st.metas)
+	st.heads = slices.Clone(st.heads)
+	st.side = st.side.clone()
+	st.shared = false
+	st.cloneOrder()
+}
@@ -0,0 +1,142 @@
+package table
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestFreeze 测试冻结后读操作正常、写操作失败
+func TestFreeze(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 10; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	table.Freeze()
+
+	if !table.Frozen() {
+		t.Fatalf("冻结后 Frozen 应返回 true")
+	}
+	if v := table.Find("key-1"); v != 1 {
+		t.Errorf("冻结后查找失败, 返回 %v", v)
+	}
+	if !table.Contains("key-2") || table.Size() != 10 || len(table.Keys()) != 10 {
+		t.Errorf("冻结后读操作结果错误")
+	}
+	var buf bytes.Buffer
+	if _, err := table.WriteTo(&buf); err != nil {
+		t.Errorf("冻结后 WriteTo 不应报错: %v", err)
+	}
+
+	panics := map[string]func(){
+		"Insert":           func() { table.Insert("new", 1) },
+		"Insert 已有键":       func() { table.Insert("key-1", 100) },
+		"Swap":             func() { table.Swap("key-1", 100) },
+		"Delete":           func() { table.Delete("key-1") },
+		"Delete 不存在的键":     func() { table.Delete("absent") },
+		"Pop":              func() { table.Pop() },
+		"CompareAndSwap":   func() { table.CompareAndSwap("key-1", 1, 2, nil) },
+		"CompareAndDelete": func() { table.CompareAndDelete("key-1", 1, nil) },
+		"MapValues":        func() { table.MapValues(func(k, v any) any { return v }) },
+		"Merge":            func() { table.Merge(NewTable(8), true) },
+		"Expand":           func() { table.Expand(1024) },
+		"Reserve":          func() { table.Reserve(1024) },
+	}
+	for name, fn := range panics {
+		func() {
+			defer func() {
+				if err, _ := recover().(error); !errors.Is(err, ErrFrozen) {
+					t.Errorf("%s 期望以 ErrFrozen panic, 实际为 %v", name, err)
+				}
+			}()
+			fn()
+		}()
+	}
+
+	if err := table.InsertBatch([]any{"a"}, []any{1}); !errors.Is(err, ErrFrozen) {
+		t.Errorf("InsertBatch 期望返回 ErrFrozen, 实际为 %v", err)
+	}
+	if err := table.SetLoadFactor(0.5); !errors.Is(err, ErrFrozen) {
+		t.Errorf("SetLoadFactor 期望返回 ErrFrozen, 实际为 %v", err)
+	}
+	if _, err := table.Increment("key-1", 1); !errors.Is(err, ErrFrozen) {
+		t.Errorf("Increment 期望返回 ErrFrozen, 实际为 %v", err)
+	}
+	if _, err := table.ReadFrom(&buf); !errors.Is(err, ErrFrozen) {
+		t.Errorf("ReadFrom 期望返回 ErrFrozen, 实际为 %v", err)
+	}
+
+	// 所有失败的写操作都不应修改表
+	for i := 0; i < 10; i++ {
+		if v := table.Find(fmt.Sprintf("key-%d", i)); v != i {
+			t.Errorf("写操作失败后 key-%d 被修改, 返回 %v", i, v)
+		}
+	}
+	if table.Size() != 10 {
+		t.Errorf("写操作失败后 size 被修改, 实际为 %d", table.Size())
+	}
+}
+
+// TestSnapshot 测试修改原表不会影响快照
+func TestSnapshot(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithInPlaceResize()}} {
+		table := NewTable(8, opts...)
+		for i := 0; i < 10; i++ {
+			table.Insert(i, i)
+		}
+
+		snap := table.Snapshot()
+		if !snap.Frozen() {
+			t.Errorf("快照应为只读")
+		}
+		if &snap.entries[0] != &table.entries[0] {
+			t.Errorf("快照创建时应共享底层数组")
+		}
+
+		table.Insert(0, 100)
+		table.Delete(1)
+		table.CompareAndSwap(2, 2, 200, nil)
+		table.MapValues(func(k, v any) any { return v })
+		for i := 10; i < 100; i++ {
+			table.Insert(i, i)
+		}
+		table.Shrink()
+
+		if snap.Size() != 10 || snap.Capacity() != 16 {
+			t.Errorf("快照的 size 或容量被修改, size=%d, 容量=%d", snap.Size(), snap.Capacity())
+		}
+		for i := 0; i < 10; i++ {
+			if v := snap.Find(i); v != i {
+				t.Errorf("快照中 %d 被修改, 返回 %v", i, v)
+			}
+		}
+		if snap.Contains(50) {
+			t.Errorf("快照中不应出现之后插入的键")
+		}
+		if err := snap.Validate(); err != nil {
+			t.Errorf("快照校验失败: %v", err)
+		}
+
+		if v := table.Find(0); v != 100 || table.Contains(1) || table.Find(2) != 200 || table.Size() != 99 {
+			t.Errorf("原表修改结果错误")
+		}
+	}
+}
+
+// TestSnapshotSingleCopy 测试创建快照后只在第一次写入时复制
+func TestSnapshotSingleCopy(t *testing.T) {
+	table := NewTable(64)
+	for i := 0; i < 10; i++ {
+		table.Insert(i, i)
+	}
+	table.Snapshot()
+
+	table.Insert(0, 1)
+	backing := &table.entries[0]
+	table.Insert(1, 2)
+	table.Delete(3)
+	if &table.entries[0] != backing {
+		t.Errorf("第一次写入复制后不应再次复制")
+	}
+}
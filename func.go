@@ -0,0 +1,116 @@
+package table
+
+import "strings"
+
+// CountFunc 返回满足 pred 的键值对数量
+func (st *Table) CountFunc(pred func(key, value any) bool) int {
+	count := 0
+	now := st.expireNow()
+	for i := 0; i < st.capacity; i++ {
+		if !st.live(i, now) {
+			continue
+		}
+		if pred(st.entries[i].key, st.valueAt(i)) {
+			count++
+		}
+	}
+	return count
+}
+
+// Filter 返回一个新表，只包含满足 pred 的键值对，新表沿用当前表的配置
+func (st *Table) Filter(pred func(key, value any) bool) *Table {
+	matched := make([]int, 0)
+	now := st.expireNow()
+	for i := 0; i < st.capacity; i++ {
+		if !st.live(i, now) {
+			continue
+		}
+		if pred(st.entries[i].key, st.valueAt(i)) {
+			matched = append(matched, i)
+		}
+	}
+
+	// 按匹配数量预先分配容量
+	res := st.newLike(CapacityFor(len(matched), st.loadFactor))
+	for _, i := range matched {
+		res.Insert(st.entries[i].key, st.valueAt(i))
+	}
+	return res
+}
+
+// DeleteFunc 原地删除所有满足 pred 的键值对，返回删除的数量，与 Filter 相反
+// 删除后开启了自动缩容时按阈值缩容，否则删除标记占多数时调用 Compact 清理；pred 中不能修改表
+func (st *Table) DeleteFunc(pred func(key, value any) bool) int {
+	st.checkWritable()
+
+	removed := 0
+	now := st.expireNow()
+	for i := 0; i < st.capacity; i++ {
+		if !st.live(i, now) {
+			continue
+		}
+		if pred(st.entries[i].key, st.valueAt(i)) {
+			st.clearSlot(i)
+			removed++
+		}
+	}
+
+	if removed > 0 {
+		st.maybeAutoShrink()
+		st.maybeAutoCompact()
+		if st.deleted > st.size {
+			st.Compact()
+		}
+	}
+	return removed
+}
+
+// DeletePrefix 删除所有以 prefix 开头的字符串键，返回删除的数量，非字符串键会被跳过
+func (st *Table) DeletePrefix(prefix string) int {
+	return st.DeleteFunc(func(key, value any) bool {
+		s, ok := key.(string)
+		return ok && strings.HasPrefix(s, prefix)
+	})
+}
+
+// MapValues 把每个键值对的值原地替换为 fn(key, value)，不修改键也不会触发扩容
+func (st *Table) MapValues(fn func(key, value any) any) {
+	st.checkWritable()
+	st.unshare()
+
+	now := st.expireNow()
+	for i := 0; i < st.capacity; i++ {
+		if !st.live(i, now) {
+			continue
+		}
+		st.entries[i].value = st.wrapValue(fn(st.entries[i].key, st.valueAt(i)))
+	}
+}
+
+// Max 返回值最大的键值对，less 用于比较两个值，表为空时 ok 为 false
+// 存在多个最大值时返回槽位顺序中的第一个
+func (st *Table) Max(less func(a, b any) bool) (key, value any, ok bool) {
+	return st.extreme(func(candidate, current any) bool {
+		return less(current, candidate)
+	})
+}
+
+// Min 返回值最小的键值对，less 用于比较两个值，表为空时 ok 为 false
+// 存在多个最小值时返回槽位顺序中的第一个
+func (st *Table) Min(less func(a, b any) bool) (key, value any, ok bool) {
+	return st.extreme(less)
+}
+
+// extreme 按槽位顺序扫描，返回 better(candidate, current) 意义下的最优键值对
+func (st *Table) extreme(better func(candidate, current any) bool) (key, value any, ok bool) {
+	now := st.expireNow()
+	for i := 0; i < st.capacity; i++ {
+		if !st.live(i, now) {
+			continue
+		}
+		if !ok || better(st.valueAt(i), value) {
+			key, value, ok = st.entries[i].key, st.valueAt(i), true
+		}
+	}
+	return key, value, ok
+}
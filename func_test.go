@@ -0,0 +1,201 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestCountFunc 测试按值范围和按键前缀计数
+func TestCountFunc(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 200; i++ {
+		table.Insert(fmt.Sprintf("user:%d", i), i)
+	}
+	for i := 0; i < 50; i++ {
+		table.Insert(fmt.Sprintf("admin:%d", i), i)
+	}
+	// 删除标记不应被计入
+	for i := 150; i < 200; i++ {
+		table.Delete(fmt.Sprintf("user:%d", i))
+	}
+
+	greater := table.CountFunc(func(key, value any) bool {
+		return value.(int) > 100
+	})
+	if greater != 49 {
+		t.Errorf("值大于 100 的数量期望为 49, 实际为 %d", greater)
+	}
+
+	admins := table.CountFunc(func(key, value any) bool {
+		return strings.HasPrefix(key.(string), "admin:")
+	})
+	if admins != 50 {
+		t.Errorf("admin: 前缀的数量期望为 50, 实际为 %d", admins)
+	}
+
+	all := table.CountFunc(func(key, value any) bool { return true })
+	if all != table.Size() {
+		t.Errorf("全部计数期望为 %d, 实际为 %d", table.Size(), all)
+	}
+}
+
+// TestFilter 测试过滤出值为偶数的键值对
+func TestFilter(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 100; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+
+	even := table.Filter(func(key, value any) bool {
+		return value.(int)%2 == 0
+	})
+	if even.Size() != 50 {
+		t.Errorf("过滤后 size 期望为 50, 实际为 %d", even.Size())
+	}
+	if even.ResizeCount() != 0 {
+		t.Errorf("预先分配容量后不应扩容, 实际扩容 %d 次", even.ResizeCount())
+	}
+	for i := 0; i < 100; i++ {
+		v := even.Find(fmt.Sprintf("key-%d", i))
+		if i%2 == 0 && v != i {
+			t.Errorf("过滤后查找 key-%d 失败, 返回 %v", i, v)
+		}
+		if i%2 == 1 && v != nil {
+			t.Errorf("过滤后不应包含 key-%d, 返回 %v", i, v)
+		}
+	}
+	if table.Size() != 100 {
+		t.Errorf("Filter 不应修改原表, size=%d", table.Size())
+	}
+
+	none := table.Filter(func(key, value any) bool { return false })
+	if none.Size() != 0 {
+		t.Errorf("全部过滤后 size 期望为 0, 实际为 %d", none.Size())
+	}
+}
+
+// TestDeleteFunc 测试删除所有值为奇数的键值对
+func TestDeleteFunc(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 100; i++ {
+		table.Insert(i, i)
+	}
+
+	removed := table.DeleteFunc(func(key, value any) bool {
+		return value.(int)%2 == 1
+	})
+	if removed != 50 || table.Size() != 50 {
+		t.Errorf("期望删除 50 个, 剩余 50 个, 实际删除 %d, 剩余 %d", removed, table.Size())
+	}
+	for i := 0; i < 100; i++ {
+		if table.Contains(i) != (i%2 == 0) {
+			t.Errorf("key=%d 是否存在的结果错误", i)
+		}
+	}
+	// 删除标记占多数时已经清理
+	if table.deleted > table.size {
+		t.Errorf("删除后删除标记 %d 不应多于键值对 %d", table.deleted, table.size)
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+
+	if n := table.DeleteFunc(func(key, value any) bool { return false }); n != 0 {
+		t.Errorf("没有满足条件的键值对时期望返回 0, 实际为 %d", n)
+	}
+}
+
+// TestDeletePrefix 测试按前缀删除字符串键
+func TestDeletePrefix(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("user:1", 1)
+	table.Insert("user:2", 2)
+	table.Insert("admin:1", 3)
+	table.Insert(42, "not a string")
+
+	if n := table.DeletePrefix("user:"); n != 2 {
+		t.Errorf("期望删除 2 个, 实际为 %d", n)
+	}
+	if table.Size() != 2 || table.Find("admin:1") != 3 || table.Find(42) != "not a string" {
+		t.Errorf("删除后期望只剩 admin:1 和非字符串键, 实际为 %v", table.ToMap())
+	}
+	if n := table.DeletePrefix("user:"); n != 0 {
+		t.Errorf("再次删除期望返回 0, 实际为 %d", n)
+	}
+}
+
+// TestMapValues 测试把所有整数值翻倍
+func TestMapValues(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 50; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	table.Delete("key-0")
+	capacity := table.Capacity()
+
+	calls := 0
+	table.MapValues(func(key, value any) any {
+		calls++
+		return value.(int) * 2
+	})
+
+	if calls != 49 {
+		t.Errorf("fn 期望调用 49 次, 实际为 %d", calls)
+	}
+	if table.Size() != 49 || table.Capacity() != capacity {
+		t.Errorf("MapValues 不应改变 size 和容量, size=%d, 容量=%d", table.Size(), table.Capacity())
+	}
+	for i := 1; i < 50; i++ {
+		if v := table.Find(fmt.Sprintf("key-%d", i)); v != i*2 {
+			t.Errorf("key-%d 期望为 %d, 实际为 %v", i, i*2, v)
+		}
+	}
+	if v := table.Find("key-0"); v != nil {
+		t.Errorf("已删除的键不应出现, 返回 %v", v)
+	}
+}
+
+// TestMinMax 测试按比较函数查找最大值和最小值
+func TestMinMax(t *testing.T) {
+	less := func(a, b any) bool { return a.(int) < b.(int) }
+
+	table := NewTable(8)
+	if _, _, ok := table.Max(less); ok {
+		t.Errorf("空表 Max 应返回 ok=false")
+	}
+	if _, _, ok := table.Min(less); ok {
+		t.Errorf("空表 Min 应返回 ok=false")
+	}
+
+	for i := 0; i < 100; i++ {
+		table.Insert(fmt.Sprintf("player-%d", i), (i*37)%101)
+	}
+	table.Delete("player-30") // (30*37)%101 = 100, 删除当前最大值
+
+	key, value, ok := table.Max(less)
+	if !ok || value != 99 {
+		t.Errorf("Max 期望值为 99, 实际为 %v, ok=%v", value, ok)
+	}
+	if table.Find(key) != value {
+		t.Errorf("Max 返回的键值不匹配, key=%v, value=%v", key, value)
+	}
+
+	key, value, ok = table.Min(less)
+	if !ok || value != 0 || key != "player-0" {
+		t.Errorf("Min 期望为 player-0=0, 实际为 %v=%v, ok=%v", key, value, ok)
+	}
+
+	// 存在并列最大值时多次调用结果一致
+	table.Insert("tie-a", 1000)
+	table.Insert("tie-b", 1000)
+	first, _, _ := table.Max(less)
+	for i := 0; i < 10; i++ {
+		if k, v, _ := table.Max(less); k != first || v != 1000 {
+			t.Errorf("并列时结果应一致, 期望 %v, 实际为 %v=%v", first, k, v)
+		}
+	}
+	if first != "tie-a" && first != "tie-b" {
+		t.Errorf("并列时应返回其中一个, 实际为 %v", first)
+	}
+}
@@ -0,0 +1,106 @@
+package table
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/cespare/xxhash"
+)
+
+// hash 计算键的哈希值
+// 设置了自定义哈希函数时直接使用，其次使用 WithTypedHash 为键的类型注册的哈希函数，否则使用混入种子的默认哈希
+func (st *Table) hash(key any) uint64 {
+	if st.hashFn != nil {
+		return st.hashFn(key)
+	}
+	if st.typedHash != nil {
+		if fn, ok := st.typedHash[reflect.TypeOf(key)]; ok {
+			return fn(key)
+		}
+	}
+	return st.defaultHash(key)
+}
+
+// defaultHash 是默认哈希，int/int64/uint64 键直接混淆整数位，string 键直接计算哈希，避免格式化为字符串；
+// 指针键按地址计算哈希，其它键格式化为字符串后计算哈希
+func (st *Table) defaultHash(key any) uint64 {
+	switch k := key.(type) {
+	case int:
+		return mix64(uint64(k) ^ st.seed)
+	case int64:
+		return mix64(uint64(k) ^ st.seed)
+	case uint64:
+		return mix64(k ^ st.seed)
+	case string:
+		return st.stringHash(k)
+	}
+
+	// 指针和通道按地址比较，也按地址计算哈希：格式化指针会输出指向的内容，内容改变后就找不到原来的键
+	switch v := reflect.ValueOf(key); v.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Chan:
+		return mix64(uint64(v.Pointer()) ^ st.seed ^ typeID(key))
+	}
+	return st.formatHash(key)
+}
+
+// stringHash 是字符串键的默认哈希，直接对字符串内容计算哈希
+func (st *Table) stringHash(s string) uint64 {
+	return mix64(xxhash.Sum64String(s) ^ st.seed)
+}
+
+// formatHash 将键格式化为字符串后计算哈希，适用于任意类型的键
+// 同时混入键的动态类型，格式化结果相同但类型不同的键（例如 String 方法返回相同文本的两个类型）哈希值不同
+func (st *Table) formatHash(key any) uint64 {
+	return mix64(xxhash.Sum64String(fmt.Sprintf("%v", key)) ^ st.seed ^ typeID(key))
+}
+
+// typeID 返回键的动态类型的标识，同一进程中每个类型唯一，nil 返回 0
+func typeID(key any) uint64 {
+	t := reflect.TypeOf(key)
+	if t == nil {
+		return 0
+	}
+	return mix64(uint64(reflect.ValueOf(t).Pointer()))
+}
+
+// Rehash 更换哈希函数并重建表，所有键值对按新的哈希值重新放置，newFn 为 nil 时恢复默认哈希
+// 可用于摆脱对当前数据冲突严重的哈希函数；容量不变，不会触发 OnResize 回调。
+// 重建时总是分配新数组，newFn panic 时表保持调用前的状态，panic 会继续向上传递。
+func (st *Table) Rehash(newFn func(any) uint64) {
+	st.checkWritable()
+
+	saved := *st
+	defer func() {
+		if r := recover(); r != nil {
+			*st = saved
+			panic(r)
+		}
+	}()
+
+	st.hashFn = newFn
+	st.reinsertAll(st.capacity)
+	st.rebuildBloom()
+	st.popCursor = 0
+}
+
+// mix64 对哈希值做一次 splitmix64 混淆，使种子影响所有位
+func mix64(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// randomSeed 从 crypto/rand 生成随机种子
+func randomSeed() uint64 {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return uint64(time.Now().UnixNano())
+	}
+	return binary.LittleEndian.Uint64(buf[:])
+}
@@ -0,0 +1,317 @@
+package table
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func slotsOf(table *Table, keys []string) []int {
+	slots := make([]int, len(keys))
+	for i, k := range keys {
+		slots[i] = table.lookup(k)
+	}
+	return slots
+}
+
+// TestSeededHash 测试不同种子下相同的键落在不同的槽位
+func TestSeededHash(t *testing.T) {
+	keys := make([]string, 200)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+
+	build := func(opts ...Option) *Table {
+		table := NewTable(8, opts...)
+		for i, k := range keys {
+			table.Insert(k, i)
+		}
+		return table
+	}
+
+	a := slotsOf(build(WithSeed(1)), keys)
+	b := slotsOf(build(WithSeed(2)), keys)
+	same := 0
+	for i := range keys {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	// 容量为 512 时随机重合的期望个数远小于 20
+	if same > 20 {
+		t.Errorf("不同种子下有 %d/%d 个键落在相同槽位", same, len(keys))
+	}
+
+	// 相同种子在多次扩容后槽位仍一致
+	c := slotsOf(build(WithSeed(1)), keys)
+	if fmt.Sprint(a) != fmt.Sprint(c) {
+		t.Errorf("相同种子的槽位应一致")
+	}
+
+	// 随机种子的表之间也应不同
+	if NewTable(8).seed == NewTable(8).seed {
+		t.Errorf("两个表的随机种子不应相同")
+	}
+}
+
+// TestSeedPreservedOnResize 测试扩容和缩容后种子保持不变
+func TestSeedPreservedOnResize(t *testing.T) {
+	table := NewTable(8, WithSeed(42))
+	for i := 0; i < 100; i++ {
+		table.Insert(i, i)
+	}
+	table.Shrink()
+	table.Expand(1024)
+	if table.seed != 42 {
+		t.Errorf("调整容量后种子期望为 42, 实际为 %d", table.seed)
+	}
+	for i := 0; i < 100; i++ {
+		if v := table.Find(i); v != i {
+			t.Errorf("查找 %d 失败, 返回 %v", i, v)
+		}
+	}
+}
+
+// TestRehash 测试更换为恒定哈希后所有键仍能找到
+func TestRehash(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 100; i++ {
+		table.Insert(i, i)
+	}
+	capacity := table.Capacity()
+
+	table.Rehash(func(any) uint64 { return 0 })
+	if table.Capacity() != capacity || table.Size() != 100 {
+		t.Errorf("Rehash 不应改变容量和数量, 实际为 %d, %d", table.Capacity(), table.Size())
+	}
+	for i := 0; i < 100; i++ {
+		if v := table.Find(i); v != i {
+			t.Errorf("Rehash 后查找 %d 失败, 返回 %v", i, v)
+		}
+	}
+	maxDistance := 0
+	for i := 0; i < 100; i++ {
+		if d, _ := table.ProbeDistance(i); d > maxDistance {
+			maxDistance = d
+		}
+	}
+	if maxDistance != 99 {
+		t.Errorf("恒定哈希下最长探测步数期望为 99, 实际为 %d", maxDistance)
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+
+	// 恢复默认哈希
+	table.Rehash(nil)
+	if d, _ := table.ProbeDistance(99); d > 10 {
+		t.Errorf("恢复默认哈希后探测步数不应这么长, 实际为 %d", d)
+	}
+}
+
+// TestRehashPanic 测试新哈希函数 panic 时表保持原样
+func TestRehashPanic(t *testing.T) {
+	table := NewTable(8, WithInsertionOrder())
+	for i := 0; i < 100; i++ {
+		table.Insert(i, i)
+	}
+	snap := table.Snapshot()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("新哈希函数 panic 时 Rehash 应继续 panic")
+			}
+		}()
+		table.Rehash(func(key any) uint64 {
+			if key == 50 {
+				panic("bad key")
+			}
+			return 0
+		})
+	}()
+
+	for i := 0; i < 100; i++ {
+		if v := table.Find(i); v != i {
+			t.Errorf("Rehash 失败后查找 %d 失败, 返回 %v", i, v)
+		}
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+	table.Insert(100, 100)
+	if snap.Size() != 100 || snap.Contains(100) {
+		t.Errorf("Rehash 失败后的修改不应影响快照")
+	}
+}
+
+// TestNumericHash 测试整数键走快速路径后的行为与字符串路径一致
+func TestNumericHash(t *testing.T) {
+	fast := NewTable(8, WithSeed(7))
+	slow := NewTable(8, WithSeed(7))
+	slow.hashFn = slow.formatHash
+
+	if fast.hash(1) == fast.formatHash(1) {
+		t.Errorf("int 键应使用快速路径")
+	}
+	neg := int64(-3)
+	if fast.hash(neg) != mix64(uint64(neg)^fast.seed) {
+		t.Errorf("int64 键应直接混淆整数位")
+	}
+
+	for _, tbl := range []*Table{fast, slow} {
+		for i := -500; i < 500; i++ {
+			tbl.Insert(i, i)
+			tbl.Insert(int64(i), -i)
+		}
+		tbl.Insert(uint64(1<<63), "max")
+		for i := -500; i < 500; i += 3 {
+			tbl.Delete(i)
+		}
+	}
+
+	if fast.Size() != slow.Size() {
+		t.Errorf("两条路径的 size 不一致, %d != %d", fast.Size(), slow.Size())
+	}
+	for i := -500; i < 500; i++ {
+		for _, key := range []any{i, int64(i)} {
+			v1, ok1 := fast.FindOK(key)
+			v2, ok2 := slow.FindOK(key)
+			if v1 != v2 || ok1 != ok2 {
+				t.Errorf("查找 %T(%v) 结果不一致, %v/%v != %v/%v", key, key, v1, ok1, v2, ok2)
+			}
+		}
+	}
+	if v := fast.Find(uint64(1 << 63)); v != "max" {
+		t.Errorf("查找 uint64 键失败, 返回 %v", v)
+	}
+	// 字符串 "1" 与整数 1 是不同的键
+	if v := fast.Find("1"); v != nil {
+		t.Errorf("字符串键不应找到整数键的值, 返回 %v", v)
+	}
+	if err := fast.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+type celsius struct{ v int }
+
+func (c celsius) String() string { return fmt.Sprintf("%d", c.v) }
+
+type meters struct{ v int }
+
+func (m meters) String() string { return fmt.Sprintf("%d", m.v) }
+
+// TestHashTypeIdentity 测试 String 结果相同的不同类型哈希值不同且独立存储
+func TestHashTypeIdentity(t *testing.T) {
+	table := NewTable(8)
+	a, b := celsius{10}, meters{10}
+	if fmt.Sprint(a) != fmt.Sprint(b) {
+		t.Fatalf("两个键格式化后应相同")
+	}
+	if table.hash(a) == table.hash(b) {
+		t.Errorf("不同类型的键哈希值不应相同")
+	}
+	if table.hash(a) != table.hash(celsius{10}) {
+		t.Errorf("相等的键哈希值应相同")
+	}
+
+	table.Insert(a, "temperature")
+	table.Insert(b, "length")
+	if table.Size() != 2 {
+		t.Errorf("期望 size=2, 实际为 %d", table.Size())
+	}
+	if v := table.Find(celsius{10}); v != "temperature" {
+		t.Errorf("查找 celsius 失败, 返回 %v", v)
+	}
+	if v := table.Find(meters{10}); v != "length" {
+		t.Errorf("查找 meters 失败, 返回 %v", v)
+	}
+	table.Delete(a)
+	if v := table.Find(b); v != "length" {
+		t.Errorf("删除 celsius 后 meters 不应受影响, 返回 %v", v)
+	}
+
+	// nil 键同样可以正常计算哈希
+	table.Insert(nil, "nil")
+	if v := table.Find(nil); v != "nil" {
+		t.Errorf("查找 nil 键失败, 返回 %v", v)
+	}
+}
+
+type node struct {
+	name  string
+	count int
+}
+
+// TestPointerKeyHash 测试指针键按地址计算哈希, 修改指向的内容后仍能找到
+func TestPointerKeyHash(t *testing.T) {
+	table := NewTable(8)
+
+	nodes := make([]*node, 100)
+	for i := range nodes {
+		// 内容相同的不同指针是不同的键
+		nodes[i] = &node{name: "same"}
+		table.Insert(nodes[i], i)
+	}
+	if table.Size() != len(nodes) {
+		t.Errorf("期望 size=%d, 实际为 %d", len(nodes), table.Size())
+	}
+
+	for i, n := range nodes {
+		n.count = i + 1
+		n.name = fmt.Sprintf("node-%d", i)
+	}
+	for i, n := range nodes {
+		if v := table.Find(n); v != i {
+			t.Errorf("修改内容后查找第 %d 个指针失败, 返回 %v", i, v)
+		}
+	}
+	if v := table.Find(&node{name: "node-0", count: 1}); v != nil {
+		t.Errorf("内容相同的新指针不应找到, 返回 %v", v)
+	}
+
+	// 经过 any 包装的同一个指针同样可以找到和删除
+	var key any = nodes[3]
+	if !table.Delete(key) || table.Contains(nodes[3]) {
+		t.Errorf("通过接口值删除指针键失败")
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+// TestIndexInRange 测试自定义哈希返回极端值时初始索引总在 [0, capacity) 之间
+func TestIndexInRange(t *testing.T) {
+	adversarial := []uint64{0, 1, math.MaxUint64, math.MaxUint64 - 1, 1 << 63, 1<<63 - 1, math.MaxUint32, 1 << 32}
+
+	for _, opts := range [][]Option{nil, {WithPrimeCapacity()}, {WithChaining()}, {WithCuckoo()}, {WithMinCapacity(1)}} {
+		table := NewTable(8, opts...)
+		table.hashFn = func(key any) uint64 { return adversarial[key.(int)%len(adversarial)] }
+
+		for capacity := 1; capacity <= 100; capacity++ {
+			table.capacity = capacity
+			for _, h := range adversarial {
+				if i := table.indexOf(h); i < 0 || i >= capacity {
+					t.Fatalf("容量 %d 下哈希值 %d 得到越界索引 %d", capacity, h, i)
+				}
+			}
+		}
+		table.capacity = len(table.entries)
+
+		for i := 0; i < 200; i++ {
+			table.Insert(i, i)
+		}
+		for i := 0; i < 200; i++ {
+			if idx := table.getIndex(i); idx < 0 || idx >= table.Capacity() {
+				t.Errorf("键 %d 的索引 %d 越界", i, idx)
+			}
+			if v := table.Find(i); v != i {
+				t.Errorf("查找 %d 失败, 返回 %v", i, v)
+			}
+		}
+		if err := table.Validate(); err != nil {
+			t.Errorf("校验失败: %v", err)
+		}
+	}
+}
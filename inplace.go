@@ -0,0 +1,91 @@
+package table
+
+import "slices"
+
+// rehashInPlace 在同一个底层数组中把所有键值对重新排列到新容量对应的槽位
+//
+// 底层数组容量足够时不分配内存，不够时通过 slices.Grow 扩展后再原地重排。
+// 缩容时只缩短切片长度并保留底层数组，以便之后再次扩容时复用。
+//
+// 算法：先把所有已占用槽位标记为 metaPending（保留标签），删除标记清空；
+// 然后依次处理每个待归位的槽位，沿探测序列找到第一个空槽位或待归位槽位：
+// 是自身则原地归位，是空槽位则移动过去，是待归位槽位则交换后继续处理换回来的键值对。
+// 由于每个键值对总是落在探测序列中第一个不被已归位键值对占据的槽位，查找时的探测链保持完整。
+func (st *Table) rehashInPlace(newCapacity int) {
+	oldCapacity := st.capacity
+	if newCapacity > cap(st.entries) {
+		st.entries = slices.Grow(st.entries[:oldCapacity], newCapacity-oldCapacity)
+	}
+	if newCapacity > cap(st.metas) {
+		st.metas = slices.Grow(st.metas[:oldCapacity], newCapacity-oldCapacity)
+	}
+
+	// 重排期间各数组保持长度 n，探测只使用新容量范围内的槽位
+	n := max(oldCapacity, newCapacity)
+	st.side.resize(n)
+	st.entries, st.metas = st.entries[:n], st.metas[:n]
+	// 之前缩容留下的尾部已被清空，这里仍然清理一次保证新增槽位为空
+	clear(st.entries[oldCapacity:])
+	clear(st.metas[oldCapacity:])
+	st.side.clearRange(oldCapacity, n)
+	for i := 0; i < oldCapacity; i++ {
+		switch st.metas[i] & 0x03 {
+		case metaFull:
+			st.metas[i] = st.metas[i]&^0x03 | metaPending
+		case metaDel:
+			st.entries[i] = Entry{}
+			st.metas[i] = metaEmpty
+			st.side.clearSlot(i)
+		}
+	}
+
+	st.capacity = newCapacity
+	st.deleted = 0
+
+	for i := 0; i < newCapacity; i++ {
+		for st.metas[i]&0x03 == metaPending {
+			target := st.findPending(st.entries[i].key)
+			st.metas[i] = st.metas[i]&^0x03 | metaFull
+			if target == i {
+				break
+			}
+
+			if st.metas[target]&0x03 == metaEmpty {
+				st.moveSlot(target, i)
+				break
+			}
+
+			// 目标槽位也在等待归位，交换后继续处理换回来的键值对
+			st.swapSlots(i, target)
+		}
+	}
+
+	// 缩容时把落在新容量之外的键值对放回前面，此时前面已没有待归位的槽位
+	for i := newCapacity; i < n; i++ {
+		if st.metas[i]&0x03 != metaPending {
+			continue
+		}
+		target := st.findPending(st.entries[i].key)
+		st.metas[i] = st.metas[i]&^0x03 | metaFull
+		st.moveSlot(target, i)
+	}
+	clear(st.entries[newCapacity:])
+	clear(st.metas[newCapacity:])
+	st.side.clearRange(newCapacity, n)
+	st.entries, st.metas = st.entries[:newCapacity], st.metas[:newCapacity]
+	st.side.resize(newCapacity)
+}
+
+// findPending 返回键的探测序列中第一个空槽位或待归位槽位
+func (st *Table) findPending(key any) int {
+	start := st.getIndex(key)
+	limit := st.probeLimit(st.capacity)
+	for i := 0; i < limit; i++ {
+		slot := st.probeAt(start, i, st.capacity)
+		meta := st.metas[slot] & 0x03
+		if meta == metaEmpty || meta == metaPending {
+			return slot
+		}
+	}
+	return -1
+}
@@ -0,0 +1,87 @@
+package table
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestInPlaceResize 测试原地重排在反复扩容缩容后依然正确
+func TestInPlaceResize(t *testing.T) {
+	for _, probe := range []ProbeStrategy{ProbeLinear, ProbeQuadratic} {
+		table := NewTable(8, WithInPlaceResize(), WithProbe(probe))
+		for round := 0; round < 5; round++ {
+			for i := 0; i < 1000; i++ {
+				table.Insert(fmt.Sprintf("key-%d", i), i+round)
+			}
+			for i := 0; i < 1000; i += 2 {
+				table.Delete(fmt.Sprintf("key-%d", i))
+			}
+			if err := table.Validate(); err != nil {
+				t.Fatalf("probe=%d, 第 %d 轮扩容后校验失败: %v", probe, round, err)
+			}
+
+			table.Shrink()
+			if err := table.Validate(); err != nil {
+				t.Fatalf("probe=%d, 第 %d 轮缩容后校验失败: %v", probe, round, err)
+			}
+			for i := 0; i < 1000; i++ {
+				v := table.Find(fmt.Sprintf("key-%d", i))
+				if i%2 == 0 && v != nil {
+					t.Errorf("probe=%d, key-%d 已删除, 返回 %v", probe, i, v)
+				}
+				if i%2 == 1 && v != i+round {
+					t.Errorf("probe=%d, 查找 key-%d 失败, 返回 %v", probe, i, v)
+				}
+			}
+		}
+	}
+}
+
+// TestInPlaceResizeCollisions 测试恒定哈希下的原地重排
+func TestInPlaceResizeCollisions(t *testing.T) {
+	table := NewTable(8, WithInPlaceResize())
+	table.hashFn = func(key any) uint64 { return uint64(key.(int) % 3) }
+	for i := 0; i < 100; i++ {
+		table.Insert(i, i)
+	}
+	table.Expand(1000)
+	for i := 0; i < 90; i++ {
+		table.Delete(i)
+	}
+	table.Shrink()
+	if err := table.Validate(); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+	for i := 90; i < 100; i++ {
+		if v := table.Find(i); v != i {
+			t.Errorf("查找 %d 失败, 返回 %v", i, v)
+		}
+	}
+}
+
+// TestInPlaceResizeReusesArray 测试缩容后再扩容复用原来的底层数组
+func TestInPlaceResizeReusesArray(t *testing.T) {
+	table := NewTable(1024, WithInPlaceResize())
+	// 默认哈希会格式化键并分配内存, 这里换成不分配内存的哈希只统计数组分配
+	table.hashFn = func(key any) uint64 { return uint64(key.(int)) }
+	backing := &table.entries[:1][0]
+
+	for i := 0; i < 100; i++ {
+		table.Insert(i, i)
+	}
+	table.Shrink()
+	if table.Capacity() >= 1024 {
+		t.Fatalf("期望缩容, 实际容量为 %d", table.Capacity())
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		table.Expand(1024)
+		table.Shrink()
+	})
+	if allocs != 0 {
+		t.Errorf("在原数组容量范围内调整容量不应分配内存, 实际为 %v", allocs)
+	}
+	if &table.entries[:1][0] != backing {
+		t.Errorf("期望复用原来的底层数组")
+	}
+}
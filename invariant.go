@@ -0,0 +1,9 @@
+//go:build !tabledebug
+
+package table
+
+// invariantChecks 表示是否以 tabledebug 标签编译
+const invariantChecks = false
+
+// checkInvariants 默认构建中为空操作，不增加任何开销；使用 -tags tabledebug 编译时在每次修改后校验内部结构
+func (st *Table) checkInvariants() {}
@@ -0,0 +1,15 @@
+//go:build tabledebug
+
+package table
+
+import "fmt"
+
+// invariantChecks 表示是否以 tabledebug 标签编译
+const invariantChecks = true
+
+// checkInvariants 以 tabledebug 标签编译时在每次修改后校验内部结构，发现损坏立即 panic
+func (st *Table) checkInvariants() {
+	if err := st.Validate(); err != nil {
+		panic(fmt.Errorf("table: invariant violated: %w", err))
+	}
+}
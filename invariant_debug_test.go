@@ -0,0 +1,50 @@
+//go:build tabledebug
+
+package table
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestInvariantChecksPass 测试正常的插入、删除和扩缩容不会触发不变量检查
+func TestInvariantChecksPass(t *testing.T) {
+	table := NewTable(4)
+	for i := 0; i < 200; i++ {
+		table.Insert(i, i)
+	}
+	for i := 0; i < 200; i += 2 {
+		table.Delete(i)
+	}
+	table.ShrinkToFit()
+	if table.Size() != 100 {
+		t.Errorf("期望大小为 100, 实际为 %d", table.Size())
+	}
+
+	// 与自身不相等的键无法查找，但插入本身合法，不应被当作损坏
+	table.Insert(math.NaN(), 1)
+	table.Insert([]int{1}, 2)
+	table.Expand(table.Capacity() * 2)
+	if table.Size() != 102 {
+		t.Errorf("期望大小为 102, 实际为 %d", table.Size())
+	}
+}
+
+// TestInvariantChecksPanic 测试内部结构损坏后的下一次修改会 panic
+func TestInvariantChecksPanic(t *testing.T) {
+	table := NewTable(16)
+	table.Insert(1, 1)
+	table.size++
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("期望结构损坏时 panic")
+		}
+		if err, ok := r.(error); !ok || !strings.Contains(err.Error(), "invariant violated") {
+			t.Errorf("期望 panic 说明不变量被破坏, 实际为 %v", r)
+		}
+	}()
+	table.Insert(2, 2)
+}
@@ -0,0 +1,18 @@
+//go:build !tabledebug
+
+package table
+
+import "testing"
+
+// TestInvariantChecksDisabled 测试默认构建不做不变量检查，结构损坏时修改不会 panic
+func TestInvariantChecksDisabled(t *testing.T) {
+	table := NewTable(16)
+	table.Insert(1, 1)
+	table.size++
+
+	table.Insert(2, 2)
+	table.Delete(1)
+	if table.Validate() == nil {
+		t.Error("期望 Validate 仍能发现损坏")
+	}
+}
@@ -0,0 +1,77 @@
+package table
+
+import "container/list"
+
+// Iterator 按需逐个遍历表中键值对的游标，可以随时暂停和继续
+//
+//	it := st.NewIterator()
+//	for it.Next() {
+//		fmt.Println(it.Key(), it.Value())
+//	}
+//
+// 遍历顺序与 Range 相同。创建迭代器后修改表（插入、删除、调整容量等）会使迭代器失效，
+// 之后的结果是未定义的
+type Iterator struct {
+	st  *Table
+	now int64
+	// 当前键值对所在的槽位，-1 表示尚未开始或已经结束
+	slot int
+	// 按槽位遍历时下一个要检查的槽位
+	pos int
+	// 按插入顺序遍历时当前的链表节点
+	elem    *list.Element
+	started bool
+}
+
+// NewIterator 返回位于第一个键值对之前的迭代器，需要先调用 Next
+func (st *Table) NewIterator() *Iterator {
+	return &Iterator{st: st, now: st.expireNow(), slot: -1}
+}
+
+// Next 移动到下一个键值对，没有更多键值对时返回 false
+func (it *Iterator) Next() bool {
+	st := it.st
+	if st.order != nil {
+		if !it.started {
+			it.elem = st.order.Front()
+			it.started = true
+		} else if it.elem != nil {
+			it.elem = it.elem.Next()
+		}
+		for ; it.elem != nil; it.elem = it.elem.Next() {
+			if slot := st.lookupSlot(it.elem.Value); slot >= 0 && st.live(slot, it.now) {
+				it.slot = slot
+				return true
+			}
+		}
+		it.slot = -1
+		return false
+	}
+
+	for it.pos < st.capacity {
+		i := it.pos
+		it.pos++
+		if st.live(i, it.now) {
+			it.slot = i
+			return true
+		}
+	}
+	it.slot = -1
+	return false
+}
+
+// Key 返回当前键值对的键，Next 返回 true 之前或返回 false 之后为 nil
+func (it *Iterator) Key() any {
+	if it.slot < 0 {
+		return nil
+	}
+	return it.st.entries[it.slot].key
+}
+
+// Value 返回当前键值对的值，Next 返回 true 之前或返回 false 之后为 nil
+func (it *Iterator) Value() any {
+	if it.slot < 0 {
+		return nil
+	}
+	return it.st.valueAt(it.slot)
+}
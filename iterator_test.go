@@ -0,0 +1,60 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestIterator 测试迭代器遍历所有键值对
+func TestIterator(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 100; i++ {
+		table.Insert(i, i*10)
+	}
+	for i := 0; i < 100; i += 3 {
+		table.Delete(i)
+	}
+
+	got := make(map[any]any)
+	it := table.NewIterator()
+	if it.Key() != nil || it.Value() != nil {
+		t.Errorf("调用 Next 之前 Key 和 Value 应为 nil")
+	}
+	for it.Next() {
+		if _, ok := got[it.Key()]; ok {
+			t.Errorf("键 %v 被遍历了多次", it.Key())
+		}
+		got[it.Key()] = it.Value()
+	}
+	if !reflect.DeepEqual(got, table.ToMap()) {
+		t.Errorf("迭代结果与 ToMap 不一致")
+	}
+	if it.Next() || it.Key() != nil {
+		t.Errorf("结束后 Next 应继续返回 false")
+	}
+
+	if NewTable(8).NewIterator().Next() {
+		t.Errorf("空表的迭代器不应有任何键值对")
+	}
+}
+
+// TestIteratorInsertionOrder 测试开启 WithInsertionOrder 时迭代器按插入顺序遍历并可以暂停
+func TestIteratorInsertionOrder(t *testing.T) {
+	table := NewTable(8, WithInsertionOrder())
+	for _, k := range []string{"c", "a", "d", "b"} {
+		table.Insert(k, k)
+	}
+
+	it := table.NewIterator()
+	var keys []any
+	for i := 0; i < 2 && it.Next(); i++ {
+		keys = append(keys, it.Key())
+	}
+	// 暂停后继续
+	for it.Next() {
+		keys = append(keys, it.Key())
+	}
+	if !reflect.DeepEqual(keys, []any{"c", "a", "d", "b"}) {
+		t.Errorf("迭代顺序应与插入顺序一致, 实际为 %v", keys)
+	}
+}
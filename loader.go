@@ -0,0 +1,26 @@
+package table
+
+// WithLoader 设置加载函数，把表变成读穿透缓存：
+// Find、FindOK、FindOr 和 FindString 找不到键时调用 loader，loader 返回 true 时插入结果并返回，返回 false 时视为不存在。
+// loader 在查找结束之后、插入之前调用，期间表没有进行中的修改，loader 中可以读写表；
+// 表本身不加锁，多个 goroutine 共享时由调用方加锁，应避免在持有写锁时调用可能阻塞的 loader。
+// 冻结的表（包括快照）不调用 loader；GetOrInsert、Contains 等其它方法也不调用
+func WithLoader(loader func(key any) (any, bool)) Option {
+	return func(st *Table) {
+		st.loader = loader
+	}
+}
+
+// load 调用加载函数并插入加载到的值，没有设置加载函数或表已冻结时返回不存在
+func (st *Table) load(key any) (any, bool) {
+	if st.loader == nil || st.frozen {
+		return nil, false
+	}
+
+	value, ok := st.loader(key)
+	if !ok {
+		return nil, false
+	}
+	st.put(key, value, 0)
+	return value, true
+}
@@ -0,0 +1,78 @@
+package table
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLoader 测试找不到键时调用加载函数, 加载结果被缓存
+func TestLoader(t *testing.T) {
+	calls := map[any]int{}
+	table := NewTable(8, WithLoader(func(key any) (any, bool) {
+		calls[key]++
+		s, ok := key.(string)
+		if !ok || !strings.HasPrefix(s, "user-") {
+			return nil, false
+		}
+		return strings.ToUpper(s), true
+	}))
+
+	for i := 0; i < 3; i++ {
+		if v := table.Find("user-1"); v != "USER-1" {
+			t.Errorf("期望加载到 USER-1, 实际为 %v", v)
+		}
+	}
+	if calls["user-1"] != 1 {
+		t.Errorf("加载后应缓存, 期望调用 1 次, 实际为 %d", calls["user-1"])
+	}
+	if table.Size() != 1 {
+		t.Errorf("加载的值应存入表中, size=%d", table.Size())
+	}
+
+	// 加载失败时不缓存, 每次都会重新调用
+	for i := 0; i < 2; i++ {
+		if v, ok := table.FindOK("other"); ok || v != nil {
+			t.Errorf("加载失败期望返回不存在, 实际为 %v, %v", v, ok)
+		}
+	}
+	if calls["other"] != 2 || table.Contains("other") {
+		t.Errorf("加载失败不应缓存, 调用次数 %d", calls["other"])
+	}
+
+	if v := table.FindOr("user-2", "fallback"); v != "USER-2" {
+		t.Errorf("FindOr 期望加载到 USER-2, 实际为 %v", v)
+	}
+	if v := table.FindOr(42, "fallback"); v != "fallback" {
+		t.Errorf("加载失败时 FindOr 期望返回 fallback, 实际为 %v", v)
+	}
+	if v, ok := table.FindString("user-3"); !ok || v != "USER-3" {
+		t.Errorf("FindString 期望加载到 USER-3, 实际为 %v", v)
+	}
+
+	// GetOrInsert 与 Contains 不调用加载函数
+	if _, inserted := table.GetOrInsert("user-4", "given"); !inserted || table.Find("user-4") != "given" {
+		t.Errorf("GetOrInsert 不应调用加载函数")
+	}
+	table.Contains("user-5")
+	if calls["user-4"] != 0 || calls["user-5"] != 0 {
+		t.Errorf("GetOrInsert 与 Contains 不应调用加载函数")
+	}
+
+	// 空表的批量查找同样调用加载函数
+	empty := NewTable(8, WithLoader(func(key any) (any, bool) {
+		return key.(string) + "!", key != "missing"
+	}))
+	if results := empty.FindBatch([]any{"x", "missing"}); results[0] != "x!" || results[1] != nil {
+		t.Errorf("FindBatch 期望加载到 [x! <nil>], 实际为 %v", results)
+	}
+	empty.Clear()
+	if results, found := empty.FindBatchOK([]any{"y", "missing"}); results[0] != "y!" || !found[0] || found[1] {
+		t.Errorf("FindBatchOK 期望加载到 y!, 实际为 %v, %v", results, found)
+	}
+
+	// 快照不调用加载函数
+	snap := table.Snapshot()
+	if v := snap.Find("user-6"); v != nil || calls["user-6"] != 0 {
+		t.Errorf("快照不应调用加载函数, 返回 %v", v)
+	}
+}
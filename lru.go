@@ -0,0 +1,43 @@
+package table
+
+import "container/list"
+
+// touch 把槽位中的键标记为最近使用，开启 WithTimestamps 时同时刷新访问时间
+// 冻结的表（包括快照）与原表共享链表，不更新使用顺序
+func (st *Table) touch(slot int) {
+	if st.frozen {
+		return
+	}
+	if st.timestamps {
+		st.unshare()
+		st.side.accessedAt[slot] = st.now().UnixNano()
+	}
+	if st.lru != nil {
+		st.lru.MoveToBack(st.side.lru[slot])
+	}
+}
+
+// evictOverflow 键值对数量超过上限时淘汰最久未使用的键
+func (st *Table) evictOverflow() {
+	if st.maxEntries <= 0 {
+		return
+	}
+	for st.size > st.maxEntries {
+		oldest := st.lru.Front()
+		st.removeAt(st.elementSlot(oldest, st.side.lru))
+	}
+}
+
+// elementSlot 返回链表节点 elem 所属条目的槽位，nodes 为各槽位在该链表中的节点
+// 先按节点中保存的键查找；与自身不相等的键（NaN、无法比较的键）无法按键找到，此时逐个扫描槽位
+func (st *Table) elementSlot(elem *list.Element, nodes []*list.Element) int {
+	if slot := st.lookupSlot(elem.Value); slot >= 0 && nodes[slot] == elem {
+		return slot
+	}
+	for i := range st.entries {
+		if st.metas[i]&0x03 == metaFull && nodes[i] == elem {
+			return i
+		}
+	}
+	return -1
+}
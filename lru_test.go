@@ -0,0 +1,109 @@
+package table
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// TestMaxEntries 测试超过上限时淘汰最久未使用的键
+func TestMaxEntries(t *testing.T) {
+	const n = 20
+	table := NewTable(8, WithMaxEntries(n))
+
+	var evicted []any
+	table.OnDelete(func(key, value any) {
+		evicted = append(evicted, key)
+	})
+
+	for i := 0; i < n+5; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+		if table.Size() > n {
+			t.Fatalf("size 不应超过 %d, 实际为 %d", n, table.Size())
+		}
+	}
+
+	if table.Size() != n {
+		t.Errorf("期望 size=%d, 实际为 %d", n, table.Size())
+	}
+	for i := 0; i < 5; i++ {
+		if table.Contains(fmt.Sprintf("key-%d", i)) {
+			t.Errorf("最早插入的 key-%d 应被淘汰", i)
+		}
+	}
+	for i := 5; i < n+5; i++ {
+		if v := table.Find(fmt.Sprintf("key-%d", i)); v != i {
+			t.Errorf("key-%d 不应被淘汰, 返回 %v", i, v)
+		}
+	}
+	if fmt.Sprint(evicted) != "[key-0 key-1 key-2 key-3 key-4]" {
+		t.Errorf("淘汰顺序错误, 实际为 %v", evicted)
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+// TestMaxEntriesAccessOrder 测试查找和更新会刷新使用顺序
+func TestMaxEntriesAccessOrder(t *testing.T) {
+	table := NewTable(8, WithMaxEntries(3))
+	table.Insert("a", 1)
+	table.Insert("b", 2)
+	table.Insert("c", 3)
+
+	table.Find("a")      // 顺序变为 b c a
+	table.Insert("b", 4) // 顺序变为 c a b
+	table.Insert("d", 5) // 淘汰 c
+
+	if table.Contains("c") {
+		t.Errorf("c 应被淘汰")
+	}
+	for _, k := range []string{"a", "b", "d"} {
+		if !table.Contains(k) {
+			t.Errorf("%s 不应被淘汰", k)
+		}
+	}
+
+	// 删除后空出位置, 不再淘汰
+	table.Delete("a")
+	table.Insert("e", 6)
+	if table.Size() != 3 || !table.Contains("b") || !table.Contains("d") {
+		t.Errorf("删除后插入不应淘汰其它键")
+	}
+
+	// 快照中查找不影响原表的使用顺序
+	snap := table.Snapshot()
+	snap.Find("b")
+	table.Insert("f", 7)
+	if table.Contains("b") {
+		t.Errorf("快照中的查找不应刷新原表的使用顺序")
+	}
+}
+
+// TestMaxEntriesUnreachableKey 测试淘汰无法按键查找的键（无法比较的键、NaN）时不会 panic
+func TestMaxEntriesUnreachableKey(t *testing.T) {
+	table := NewTable(8, WithMaxEntries(1))
+	table.Insert([]int{1}, 1)
+	table.Insert("b", 2)
+	if table.Size() != 1 || table.Find("b") != 2 {
+		t.Errorf("无法比较的键应被淘汰, size=%d", table.Size())
+	}
+
+	nan := NewTable(8, WithMaxEntries(2))
+	var evicted []any
+	nan.OnDelete(func(key, value any) {
+		evicted = append(evicted, value)
+	})
+	for i := 0; i < 3; i++ {
+		nan.Insert(math.NaN(), i)
+	}
+	if nan.Size() != 2 {
+		t.Errorf("期望 size=2, 实际为 %d", nan.Size())
+	}
+	if fmt.Sprint(evicted) != "[0]" {
+		t.Errorf("应淘汰最早插入的 NaN 键, 实际淘汰 %v", evicted)
+	}
+	if nan.liveCount() != nan.Size() {
+		t.Errorf("淘汰后已占用槽位数 %d 与 size=%d 不一致", nan.liveCount(), nan.Size())
+	}
+}
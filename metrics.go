@@ -0,0 +1,32 @@
+package table
+
+import "sync/atomic"
+
+// recordLookup 记录一次查找是否命中
+func (st *Table) recordLookup(hit bool) {
+	if hit {
+		atomic.AddUint64(&st.hits, 1)
+	} else {
+		atomic.AddUint64(&st.misses, 1)
+	}
+}
+
+// HitCount 返回 Find、FindOK、FindOr 和 FindString 累计命中的次数，FindBatch 等逐个调用它们的方法同样计入
+// GetOrInsert、GetOrCompute、InsertIfAbsent 等插入类方法查找已有的键时不计入
+func (st *Table) HitCount() uint64 {
+	return atomic.LoadUint64(&st.hits)
+}
+
+// MissCount 返回 Find、FindOK、FindOr 和 FindString 累计未命中的次数，同样不计入插入类方法
+func (st *Table) MissCount() uint64 {
+	return atomic.LoadUint64(&st.misses)
+}
+
+// HitRatio 返回命中次数占查找次数的比例，还没有查找过时返回 0
+func (st *Table) HitRatio() float64 {
+	hits, misses := st.HitCount(), st.MissCount()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
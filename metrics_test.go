@@ -0,0 +1,65 @@
+package table
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestHitMissCount 测试查找命中和未命中的计数
+func TestHitMissCount(t *testing.T) {
+	table := NewTable(8)
+	if table.HitRatio() != 0 {
+		t.Errorf("没有查找时命中率期望为 0, 实际为 %v", table.HitRatio())
+	}
+
+	table.Insert("a", 1)
+	table.Insert("b", 2)
+
+	table.Find("a")
+	table.FindOK("b")
+	table.FindOr("a", 0)
+	table.FindString("b")
+	table.Find("c")
+	table.FindOK("d")
+	table.FindString("e")
+	table.FindOr("f", 0)
+	// FindBatch 逐个调用 Find, 同样计入
+	table.FindBatch([]any{"x"})
+	// Contains 不读取值, 不计入
+	table.Contains("a")
+	// 插入类方法不计入
+	table.GetOrInsert("a", 0)
+	table.GetOrCompute("g", func() any { return 0 })
+	table.InsertIfAbsent("h", 0)
+	table.GetOrInsertBatch([]any{"a", "i"}, []any{0, 0})
+
+	if table.HitCount() != 4 || table.MissCount() != 5 {
+		t.Errorf("期望命中 4 次、未命中 5 次, 实际为 %d, %d", table.HitCount(), table.MissCount())
+	}
+	if r := table.HitRatio(); r != 4.0/9 {
+		t.Errorf("期望命中率为 4/9, 实际为 %v", r)
+	}
+}
+
+// TestHitMissCountConcurrent 测试并发查找时计数不丢失
+func TestHitMissCountConcurrent(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("a", 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				table.Find("a")
+				table.Find("b")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if table.HitCount() != 8000 || table.MissCount() != 8000 {
+		t.Errorf("期望命中和未命中各 8000 次, 实际为 %d, %d", table.HitCount(), table.MissCount())
+	}
+}
@@ -0,0 +1,35 @@
+package table
+
+import "slices"
+
+// WithMultiValue 开启多值模式：Insert 把值追加到键已有的值列表之后而不是覆盖
+// 开启后 Find 返回键的全部值组成的 []any，FindAll 返回它的副本；Delete 删除键的全部值
+func WithMultiValue() Option {
+	return func(st *Table) {
+		st.multiValue = true
+	}
+}
+
+// appendValue 把 value 追加到已有的值列表之后，existing 不是值列表时视为空列表
+// 总是分配新的切片，不会修改与快照共享的旧列表
+func appendValue(existing any, value any) []any {
+	values, _ := existing.([]any)
+	return append(values[:len(values):len(values)], value)
+}
+
+// FindAll 返回键的全部值，按插入顺序排列，键不存在时返回 nil
+// 未开启多值模式时返回只包含当前值的切片；返回的是副本，修改不会影响表
+func (st *Table) FindAll(key any) []any {
+	slot := st.lookup(key)
+	if slot < 0 {
+		return nil
+	}
+	st.touch(slot)
+
+	value := st.valueAt(slot)
+	if !st.multiValue {
+		return []any{value}
+	}
+	values, _ := value.([]any)
+	return slices.Clone(values)
+}
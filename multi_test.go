@@ -0,0 +1,61 @@
+package table
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestMultiValue 测试多值模式下同一个键的值依次追加
+func TestMultiValue(t *testing.T) {
+	table := NewTable(8, WithMultiValue())
+	table.Insert("k", 1)
+	table.Insert("k", 2)
+	table.Insert("k", 3)
+	table.Insert("other", "x")
+
+	if all := table.FindAll("k"); fmt.Sprint(all) != "[1 2 3]" {
+		t.Errorf("FindAll 期望返回 [1 2 3], 实际为 %v", all)
+	}
+	if table.Size() != 2 {
+		t.Errorf("多个值只算一个键, 期望 size=2, 实际为 %d", table.Size())
+	}
+
+	// 快照中的值列表不受之后追加的影响
+	snap := table.Snapshot()
+	table.Insert("k", 4)
+	if all := snap.FindAll("k"); fmt.Sprint(all) != "[1 2 3]" {
+		t.Errorf("快照期望保持 [1 2 3], 实际为 %v", all)
+	}
+
+	// 修改返回值不影响表
+	all := table.FindAll("k")
+	all[0] = 100
+	if v := table.FindAll("k"); fmt.Sprint(v) != "[1 2 3 4]" {
+		t.Errorf("修改返回值后期望仍为 [1 2 3 4], 实际为 %v", v)
+	}
+
+	if !table.Delete("k") {
+		t.Fatalf("删除 k 失败")
+	}
+	if all := table.FindAll("k"); all != nil {
+		t.Errorf("删除后期望返回 nil, 实际为 %v", all)
+	}
+	table.Insert("k", 5)
+	if all := table.FindAll("k"); fmt.Sprint(all) != "[5]" {
+		t.Errorf("删除后重新插入期望为 [5], 实际为 %v", all)
+	}
+}
+
+// TestFindAllSingleValue 测试未开启多值模式时 FindAll 返回当前值
+func TestFindAllSingleValue(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("k", 1)
+	table.Insert("k", 2)
+
+	if all := table.FindAll("k"); fmt.Sprint(all) != "[2]" {
+		t.Errorf("期望返回 [2], 实际为 %v", all)
+	}
+	if all := table.FindAll("missing"); all != nil {
+		t.Errorf("不存在的键期望返回 nil, 实际为 %v", all)
+	}
+}
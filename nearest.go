@@ -0,0 +1,52 @@
+package table
+
+import (
+	"math"
+	"reflect"
+)
+
+// FindNearest 返回数值键与 key 最接近的键值对，适用于用数值键做近似查找的表
+// 整数、无符号整数和浮点数类型的键都参与比较（包括底层类型为数值的自定义类型），其它键以及 NaN 键被跳过；
+// 距离相同时返回较小的键。需要扫描所有键，复杂度为 O(n)；没有数值键或 key 为 NaN 时 ok 为 false
+func (st *Table) FindNearest(key float64) (matchedKey any, value any, ok bool) {
+	if math.IsNaN(key) {
+		return nil, nil, false
+	}
+
+	best, bestDist, bestKey := -1, math.Inf(1), 0.0
+	st.forEach(func(slot int) bool {
+		k, numeric := numericKey(st.entries[slot].key)
+		if !numeric || math.IsNaN(k) {
+			return true
+		}
+		dist := math.Abs(k - key)
+		if best < 0 || dist < bestDist || dist == bestDist && k < bestKey {
+			best, bestDist, bestKey = slot, dist, k
+		}
+		return true
+	})
+	if best < 0 {
+		return nil, nil, false
+	}
+	return st.entries[best].key, st.valueAt(best), true
+}
+
+// numericKey 把数值类型的键转换为 float64，不是数值类型时返回 false
+func numericKey(key any) (float64, bool) {
+	switch k := key.(type) {
+	case int:
+		return float64(k), true
+	case float64:
+		return k, true
+	}
+
+	switch v := reflect.ValueOf(key); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,52 @@
+package table
+
+import (
+	"math"
+	"testing"
+)
+
+// TestFindNearest 测试返回数值键最接近的键值对
+func TestFindNearest(t *testing.T) {
+	table := NewTable(8)
+	table.Insert(1, "one")
+	table.Insert(5, "five")
+	table.Insert(10, "ten")
+	table.Insert("6", "string")
+
+	k, v, ok := table.FindNearest(6)
+	if !ok || k != 5 || v != "five" {
+		t.Errorf("查询 6 期望返回 5, 实际为 %v=%v, ok=%v", k, v, ok)
+	}
+	if k, _, _ := table.FindNearest(100); k != 10 {
+		t.Errorf("查询 100 期望返回 10, 实际为 %v", k)
+	}
+	// 距离相同时返回较小的键
+	if k, _, _ := table.FindNearest(3); k != 1 {
+		t.Errorf("查询 3 期望返回较小的 1, 实际为 %v", k)
+	}
+
+	table.Insert(uint8(7), "seven")
+	table.Insert(float32(5.75), "float")
+	if k, _, _ := table.FindNearest(6.9); k != uint8(7) {
+		t.Errorf("查询 6.9 期望返回 uint8(7), 实际为 %v", k)
+	}
+	if k, _, _ := table.FindNearest(5.8); k != float32(5.75) {
+		t.Errorf("查询 5.8 期望返回 float32(5.75), 实际为 %v", k)
+	}
+	if _, _, ok := table.FindNearest(math.NaN()); ok {
+		t.Errorf("查询 NaN 不应找到")
+	}
+}
+
+// TestFindNearestNoNumeric 测试没有数值键时返回 false
+func TestFindNearestNoNumeric(t *testing.T) {
+	table := NewTable(8)
+	if _, _, ok := table.FindNearest(1); ok {
+		t.Errorf("空表不应找到")
+	}
+	table.Insert("a", 1)
+	table.Insert(math.NaN(), 2)
+	if _, _, ok := table.FindNearest(1); ok {
+		t.Errorf("没有可比较的数值键时不应找到")
+	}
+}
@@ -0,0 +1,219 @@
+package table
+
+import (
+	"container/list"
+	"maps"
+	"reflect"
+	"time"
+)
+
+// Option 用于在创建表时调整配置
+type Option func(*Table)
+
+// WithAutoShrink 开启自动缩容：删除后 size 低于 threshold * capacity 时自动缩容
+// 缩容后 size 位于 threshold 与负载因子的中点，留出余量，避免在阈值附近交替插入删除时反复扩缩容；
+// threshold 必须在 (0, 1) 之间且小于负载因子（否则 NewTable 会 panic），缩容不会低于最小容量（默认为 8，见 WithMinCapacity）
+func WithAutoShrink(threshold float64) Option {
+	if threshold <= 0 || threshold >= 1 {
+		panic("table: auto shrink threshold must be in (0, 1)")
+	}
+	return func(st *Table) {
+		st.autoShrink = threshold
+	}
+}
+
+// WithCompactThreshold 开启自动整理：删除后墓碑数超过 ratio * capacity 时自动调用 Compact
+// ratio 必须在 (0, 1) 之间，适合删除频繁的场景，避免墓碑拖慢查找
+func WithCompactThreshold(ratio float64) Option {
+	if ratio <= 0 || ratio >= 1 {
+		panic("table: compact threshold must be in (0, 1)")
+	}
+	return func(st *Table) {
+		st.compactThreshold = ratio
+	}
+}
+
+// WithGrowthFactor 设置扩容时容量的增长倍数，默认为 2，f 必须大于 1
+func WithGrowthFactor(f float64) Option {
+	if f <= 1 {
+		panic("table: growth factor must be greater than 1")
+	}
+	return func(st *Table) {
+		st.growthFactor = f
+	}
+}
+
+// ProbeStrategy 开放寻址发生冲突时的探测方式
+type ProbeStrategy int
+
+const (
+	// ProbeLinear 线性探测，依次检查下一个槽位
+	ProbeLinear ProbeStrategy = iota
+	// ProbeQuadratic 二次探测，第 i 次探测的偏移为 i*(i+1)/2，可缓解线性探测的聚集问题
+	ProbeQuadratic
+)
+
+// WithProbe 设置冲突时的探测方式，默认为 ProbeLinear
+func WithProbe(p ProbeStrategy) Option {
+	if p != ProbeLinear && p != ProbeQuadratic {
+		panic("table: unknown probe strategy")
+	}
+	return func(st *Table) {
+		st.probe = p
+	}
+}
+
+// WithMaxProbe 限制插入新键时的探测距离，超过 n 时不等负载因子达到阈值就提前调整：
+// 使用默认哈希时换一个随机种子重建（每次扩容之间最多一次，之后改为扩容），使用自定义哈希时扩容，
+// 用于抵御构造的冲突键；n 必须为正数
+// 拉链法下冲突只拉长各自的桶，不影响其它键，该选项不生效
+func WithMaxProbe(n int) Option {
+	if n <= 0 {
+		panic("table: max probe must be positive")
+	}
+	return func(st *Table) {
+		st.maxProbe = n
+	}
+}
+
+// DuplicatePolicy InsertBatch 遇到同一批中重复的键时的处理方式
+type DuplicatePolicy int
+
+const (
+	// DuplicateOverwrite 后出现的值覆盖先出现的值
+	DuplicateOverwrite DuplicatePolicy = iota
+	// DuplicateError 返回 ErrDuplicateKey，不修改表
+	DuplicateError
+	// DuplicateKeepFirst 保留第一次出现的值，忽略之后重复的键
+	DuplicateKeepFirst
+)
+
+// WithDuplicateBatchPolicy 设置 InsertBatch 遇到同一批中重复的键时的处理方式，默认为 DuplicateOverwrite
+// 只影响同一批内部的重复，批中的键与表中已有的键相同时总是更新
+func WithDuplicateBatchPolicy(p DuplicatePolicy) Option {
+	if p != DuplicateOverwrite && p != DuplicateError && p != DuplicateKeepFirst {
+		panic("table: unknown duplicate policy")
+	}
+	return func(st *Table) {
+		st.duplicatePolicy = p
+	}
+}
+
+// WithSeed 指定默认哈希使用的种子，默认每个表从 crypto/rand 随机生成
+// 相同种子的表对相同的键会得到相同的槽位，便于复现问题
+func WithSeed(seed uint64) Option {
+	return func(st *Table) {
+		st.seed = seed
+	}
+}
+
+// WithKeyEqual 设置自定义的键比较函数，用于无法用 == 比较的键（例如包含切片的结构体）
+// 哈希函数必须与之保持一致：fn 认为相等的两个键必须得到相同的哈希值
+func WithKeyEqual(fn func(a, b any) bool) Option {
+	return func(st *Table) {
+		st.keyEqual = fn
+	}
+}
+
+// WithMinCapacity 设置表的最小容量，默认为 8
+// 创建表时的初始容量以及 Shrink 缩容后的容量都不会低于 n，避免缩容后又马上扩容，n 必须大于 0
+// n 可以小于 8，内存紧张时允许创建容量只有 1 到 4 的小表
+func WithMinCapacity(n int) Option {
+	if n <= 0 {
+		panic("table: min capacity must be positive")
+	}
+	return func(st *Table) {
+		st.minCapacity = n
+	}
+}
+
+// WithInPlaceResize 调整容量时复用底层数组原地重排，而不是分配新数组再逐个插入
+//
+// 缩容时会保留原来的底层数组，之后在该数组容量范围内再次扩容不会分配内存，
+// 适合容量反复涨落的表；代价是缩容不会把内存还给运行时。
+// 超出底层数组容量的扩容仍需分配新数组并复制原有条目，内存峰值与默认方式相同。
+func WithInPlaceResize() Option {
+	return func(st *Table) {
+		st.inPlace = true
+	}
+}
+
+// WithTypedHash 为指定类型的键注册哈希函数，其它类型的键仍使用默认哈希
+// 传入的 map 会被复制，之后修改不影响表；通过 Rehash 设置了哈希函数时以后者为准
+func WithTypedHash(hashers map[reflect.Type]func(any) uint64) Option {
+	typed := maps.Clone(hashers)
+	return func(st *Table) {
+		st.typedHash = typed
+	}
+}
+
+// WithClock 设置表使用的时钟，默认为 time.Now，主要用于测试过期等与时间相关的行为
+func WithClock(clock func() time.Time) Option {
+	return func(st *Table) {
+		st.clock = clock
+	}
+}
+
+// WithMaxEntries 限制最多保存 n 个键值对，插入新键超出上限时淘汰最久未使用的键
+// Find、FindOr 以及插入或更新都会把键标记为最近使用，淘汰同样会触发 OnDelete 回调
+func WithMaxEntries(n int) Option {
+	if n <= 0 {
+		panic("table: max entries must be positive")
+	}
+	return func(st *Table) {
+		st.maxEntries = n
+		st.lru = list.New()
+	}
+}
+
+// WithInsertionOrder 记录键的插入顺序，Keys、Values 和 Range 按插入顺序遍历
+// 更新已有的键不改变顺序，删除后重新插入的键排到最后
+func WithInsertionOrder() Option {
+	return func(st *Table) {
+		st.order = list.New()
+	}
+}
+
+// WithBloomFilter 在查找前先用布隆过滤器排除一定不存在的键，适合查找大多不命中的场景
+// expectedN 为预期的键数量，fpRate 为期望的误判率，必须在 (0, 1) 之间
+// 过滤器在调整容量时重建，删除较多时可以调用 Shrink 或 Expand 清除已删除键的影响
+func WithBloomFilter(expectedN int, fpRate float64) Option {
+	if expectedN <= 0 {
+		panic("table: bloom filter expected entries must be positive")
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		panic("table: bloom filter false positive rate must be in (0, 1)")
+	}
+	return func(st *Table) {
+		st.bloomN, st.bloomFP = expectedN, fpRate
+		st.bloom = newBloomFilter(expectedN, fpRate)
+	}
+}
+
+// WithChaining 使用拉链法代替开放寻址解决冲突
+// 冲突的键串在同一个桶的链表中，不存在探测序列和删除标记，负载因子较高时性能更稳定；
+// 拉链法下 WithProbe 与 WithInPlaceResize 不生效
+func WithChaining() Option {
+	return func(st *Table) {
+		st.chaining = true
+	}
+}
+
+// WithPrimeCapacity 使表的容量总是取不小于所需容量的最小质数
+// 索引仍按哈希值取模计算，质数容量可以避免哈希值低位的规律（例如都是 2 的幂的倍数）集中到少数槽位
+func WithPrimeCapacity() Option {
+	return func(st *Table) {
+		st.primeCapacity = true
+	}
+}
+
+// WithCuckoo 使用布谷鸟哈希代替开放寻址解决冲突
+// 每个键只可能位于两个固定位置之一，查找最多检查两个槽位（备用区非空时再扫描很小的备用区），
+// 查找延迟稳定，适合对读取延迟敏感的场景；插入可能需要挤动已有的键，放不下时自动扩容。
+// 布谷鸟哈希在负载较低时才能高效插入，因此负载因子默认设为 0.5；WithProbe 与 WithInPlaceResize 不生效
+func WithCuckoo() Option {
+	return func(st *Table) {
+		st.cuckoo = true
+		st.loadFactor = 0.5
+	}
+}
@@ -0,0 +1,637 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// TestAutoShrink 测试大量删除后自动缩容
+func TestAutoShrink(t *testing.T) {
+	table := NewTable(8, WithAutoShrink(0.25))
+	for i := 0; i < 1000; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	peak := table.Capacity()
+
+	for i := 0; i < 950; i++ {
+		table.Delete(fmt.Sprintf("key-%d", i))
+	}
+
+	if table.Capacity() >= peak {
+		t.Errorf("大量删除后期望容量小于 %d, 实际为 %d", peak, table.Capacity())
+	}
+	for i := 950; i < 1000; i++ {
+		if v := table.Find(fmt.Sprintf("key-%d", i)); v != i {
+			t.Errorf("自动缩容后查找 key-%d 失败, 返回 %v", i, v)
+		}
+	}
+
+	// 全部删除后也不能低于最小容量
+	for i := 950; i < 1000; i++ {
+		table.Delete(fmt.Sprintf("key-%d", i))
+	}
+	if table.Capacity() != 8 {
+		t.Errorf("全部删除后期望容量为 8, 实际为 %d", table.Capacity())
+	}
+
+	// 缩容后配置应保留, 再次增长并删除时仍会自动缩容
+	for i := 0; i < 100; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	peak = table.Capacity()
+	for i := 0; i < 100; i++ {
+		table.Delete(fmt.Sprintf("key-%d", i))
+	}
+	if table.Capacity() >= peak {
+		t.Errorf("再次删除后期望容量小于 %d, 实际为 %d", peak, table.Capacity())
+	}
+}
+
+// TestAutoShrinkDisabled 测试默认不会自动缩容
+func TestAutoShrinkDisabled(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 1000; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	peak := table.Capacity()
+	for i := 0; i < 950; i++ {
+		table.Delete(fmt.Sprintf("key-%d", i))
+	}
+	if table.Capacity() != peak {
+		t.Errorf("未开启自动缩容时容量不应变化, 期望 %d, 实际为 %d", peak, table.Capacity())
+	}
+}
+
+// TestAutoShrinkNoThrash 测试在缩容阈值附近交替插入和删除时不会反复调整容量
+func TestAutoShrinkNoThrash(t *testing.T) {
+	table := NewTable(8, WithAutoShrink(0.5))
+	for i := 0; i < 1000; i++ {
+		table.Insert(i, i)
+	}
+	for i := 0; i < 700; i++ {
+		table.Delete(i)
+	}
+
+	before := table.ResizeCount()
+	for i := 0; i < 200; i++ {
+		if i%2 == 0 {
+			table.Insert(1000+i, i)
+		} else {
+			table.Delete(1000 + i - 1)
+		}
+	}
+	if n := table.ResizeCount() - before; n > 2 {
+		t.Errorf("交替插入删除期间期望最多调整 2 次容量, 实际为 %d", n)
+	}
+	if table.Size() != 300 {
+		t.Errorf("期望 size=300, 实际为 %d", table.Size())
+	}
+
+	// 缩容阈值不能达到负载因子
+	if err := table.SetLoadFactor(0.5); err == nil {
+		t.Errorf("负载因子不大于缩容阈值时 SetLoadFactor 应返回错误")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("缩容阈值不小于负载因子时 NewTable 应 panic")
+		}
+	}()
+	NewTable(8, WithAutoShrink(0.9))
+}
+
+// TestAutoShrinkInvalidThreshold 测试非法阈值
+func TestAutoShrinkInvalidThreshold(t *testing.T) {
+	for _, threshold := range []float64{0, -0.5, 1, 2} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("threshold=%v 应 panic", threshold)
+				}
+			}()
+			WithAutoShrink(threshold)
+		}()
+	}
+}
+
+// TestCompactThreshold 测试大量删除后墓碑比例超过阈值时自动整理
+func TestCompactThreshold(t *testing.T) {
+	table := NewTable(8, WithCompactThreshold(0.2))
+	for i := 0; i < 1000; i++ {
+		table.Insert(i, i)
+	}
+	capacity := table.Capacity()
+
+	compacted := false
+	for i := 0; i < 900; i++ {
+		before := table.DeletedCount()
+		table.Delete(i)
+		if table.DeletedCount() == 0 && before > 0 {
+			compacted = true
+		}
+		if float64(table.DeletedCount()) > float64(capacity)*0.2 {
+			t.Fatalf("删除 %d 个键后墓碑数 %d 超过阈值", i+1, table.DeletedCount())
+		}
+	}
+	if !compacted {
+		t.Errorf("墓碑超过阈值后应自动整理")
+	}
+	if table.Capacity() != capacity {
+		t.Errorf("自动整理不应改变容量, 期望 %d, 实际为 %d", capacity, table.Capacity())
+	}
+	for i := 900; i < 1000; i++ {
+		if v := table.Find(i); v != i {
+			t.Errorf("自动整理后查找 %d 失败, 返回 %v", i, v)
+		}
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+
+	// 未开启时墓碑一直保留
+	plain := NewTable(8)
+	for i := 0; i < 1000; i++ {
+		plain.Insert(i, i)
+	}
+	for i := 0; i < 900; i++ {
+		plain.Delete(i)
+	}
+	if plain.DeletedCount() != 900 {
+		t.Errorf("未开启自动整理时期望 900 个墓碑, 实际为 %d", plain.DeletedCount())
+	}
+}
+
+// TestCompactThresholdInvalid 测试非法阈值
+func TestCompactThresholdInvalid(t *testing.T) {
+	for _, ratio := range []float64{0, -0.1, 1, 1.5} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("ratio=%v 应 panic", ratio)
+				}
+			}()
+			WithCompactThreshold(ratio)
+		}()
+	}
+}
+
+// TestGrowthFactor 测试自定义增长倍数
+func TestGrowthFactor(t *testing.T) {
+	table := NewTable(8, WithGrowthFactor(1.5))
+
+	var caps []int
+	for i := 0; i < 14; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+		if n := len(caps); n == 0 || caps[n-1] != table.Capacity() {
+			caps = append(caps, table.Capacity())
+		}
+	}
+
+	expected := []int{8, 12, 18, 27}
+	if fmt.Sprint(caps) != fmt.Sprint(expected) {
+		t.Errorf("期望容量变化为 %v, 实际为 %v", expected, caps)
+	}
+
+	// 批量插入同样使用增长倍数
+	batch := NewTable(8, WithGrowthFactor(1.5))
+	if err := batch.InsertBatch([]any{1, 2, 3, 4, 5, 6, 7}, []any{1, 2, 3, 4, 5, 6, 7}); err != nil {
+		t.Fatalf("InsertBatch 发生错误: %v", err)
+	}
+	if batch.Capacity() != 12 {
+		t.Errorf("批量插入后期望容量为 12, 实际为 %d", batch.Capacity())
+	}
+
+	for i := 0; i < 14; i++ {
+		if v := table.Find(fmt.Sprintf("key-%d", i)); v != i {
+			t.Errorf("查找 key-%d 失败, 返回 %v", i, v)
+		}
+	}
+}
+
+// TestGrowthFactorInvalid 测试非法增长倍数
+func TestGrowthFactorInvalid(t *testing.T) {
+	for _, f := range []float64{1, 0.5, 0, -2} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("f=%v 应 panic", f)
+				}
+			}()
+			WithGrowthFactor(f)
+		}()
+	}
+}
+
+// TestProbeStrategy 在恒定哈希的冲突场景下比较两种探测方式
+func TestProbeStrategy(t *testing.T) {
+	occupied := func(p ProbeStrategy) []int {
+		table := NewTable(64, WithProbe(p))
+		table.hashFn = func(any) uint64 { return 0 }
+
+		for i := 0; i < 10; i++ {
+			table.Insert(fmt.Sprintf("conflict-%d", i), i)
+		}
+		// 删除后再插入, 验证两种方式下探测链保持完整
+		table.Delete("conflict-3")
+		table.Insert("conflict-10", 10)
+
+		for i := 0; i <= 10; i++ {
+			v := table.Find(fmt.Sprintf("conflict-%d", i))
+			if i == 3 && v != nil {
+				t.Errorf("probe=%d, conflict-3 已删除, 返回 %v", p, v)
+			}
+			if i != 3 && v != i {
+				t.Errorf("probe=%d, 查找 conflict-%d 失败, 返回 %v", p, i, v)
+			}
+		}
+
+		var slots []int
+		for i := 0; i < table.Capacity(); i++ {
+			if table.metas[i]&0x03 == metaFull {
+				slots = append(slots, i)
+			}
+		}
+		return slots
+	}
+
+	// 线性探测下所有冲突键聚集在连续的槽位中
+	linear := occupied(ProbeLinear)
+	if fmt.Sprint(linear) != fmt.Sprint([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("线性探测期望占用连续槽位, 实际为 %v", linear)
+	}
+
+	// 二次探测下冲突键按三角数偏移分散
+	quadratic := occupied(ProbeQuadratic)
+	if fmt.Sprint(quadratic) != fmt.Sprint([]int{0, 1, 3, 6, 10, 15, 21, 28, 36, 45}) {
+		t.Errorf("二次探测期望占用分散的槽位, 实际为 %v", quadratic)
+	}
+}
+
+// TestProbeQuadraticFull 测试二次探测在非 2 的幂容量下依然能填满可用槽位
+func TestProbeQuadraticFull(t *testing.T) {
+	skipUnderInvariantChecks(t)
+	table := NewTable(12, WithProbe(ProbeQuadratic))
+	table.hashFn = func(any) uint64 { return 5 }
+
+	for i := 0; i < 1000; i++ {
+		table.Insert(i, i)
+	}
+	for i := 0; i < 1000; i += 2 {
+		table.Delete(i)
+	}
+	for i := 0; i < 1000; i++ {
+		v := table.Find(i)
+		if i%2 == 0 && v != nil {
+			t.Errorf("%d 已删除, 返回 %v", i, v)
+		}
+		if i%2 == 1 && v != i {
+			t.Errorf("查找 %d 失败, 返回 %v", i, v)
+		}
+	}
+}
+
+// TestProbeAtInRange 测试两种探测方式在各种容量下算出的索引都不越界
+func TestProbeAtInRange(t *testing.T) {
+	for _, probe := range []ProbeStrategy{ProbeLinear, ProbeQuadratic} {
+		table := NewTable(8, WithProbe(probe))
+		for capacity := 1; capacity <= 64; capacity++ {
+			for start := 0; start < capacity; start++ {
+				for i := 0; i < table.probeLimit(capacity); i++ {
+					if slot := table.probeAt(start, i, capacity); slot < 0 || slot >= capacity {
+						t.Fatalf("容量 %d 下从 %d 出发第 %d 次探测得到越界索引 %d", capacity, start, i, slot)
+					}
+				}
+			}
+		}
+	}
+}
+
+type compositeKey struct {
+	Name string
+	Tags []string
+}
+
+// TestKeyEqual 测试使用 reflect.DeepEqual 比较包含切片的结构体键
+func TestKeyEqual(t *testing.T) {
+	table := NewTable(8, WithKeyEqual(reflect.DeepEqual))
+
+	for i := 0; i < 20; i++ {
+		table.Insert(compositeKey{Name: fmt.Sprintf("k%d", i), Tags: []string{"a", "b"}}, i)
+	}
+	// 使用新构造的等价键查找和更新
+	table.Insert(compositeKey{Name: "k0", Tags: []string{"a", "b"}}, 100)
+
+	if table.Size() != 20 {
+		t.Errorf("期望 size=20, 实际为 %d", table.Size())
+	}
+	if v := table.Find(compositeKey{Name: "k0", Tags: []string{"a", "b"}}); v != 100 {
+		t.Errorf("查找 k0 期望为 100, 实际为 %v", v)
+	}
+	for i := 1; i < 20; i++ {
+		if v := table.Find(compositeKey{Name: fmt.Sprintf("k%d", i), Tags: []string{"a", "b"}}); v != i {
+			t.Errorf("查找 k%d 失败, 返回 %v", i, v)
+		}
+	}
+	if v := table.Find(compositeKey{Name: "k1", Tags: []string{"a"}}); v != nil {
+		t.Errorf("Tags 不同的键不应被找到, 返回 %v", v)
+	}
+
+	if !table.Delete(compositeKey{Name: "k1", Tags: []string{"a", "b"}}) {
+		t.Errorf("删除 k1 失败")
+	}
+	if table.Size() != 19 {
+		t.Errorf("删除后期望 size=19, 实际为 %d", table.Size())
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+// TestMinCapacity 测试缩容不会低于设置的最小容量
+func TestMinCapacity(t *testing.T) {
+	table := NewTable(8, WithMinCapacity(64), WithAutoShrink(0.25))
+	if table.Capacity() != 64 {
+		t.Errorf("初始容量期望被提升到 64, 实际为 %d", table.Capacity())
+	}
+
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 1000; i++ {
+			table.Insert(i, i)
+		}
+		for i := 0; i < 1000; i++ {
+			table.Delete(i)
+			if table.Capacity() < 64 {
+				t.Fatalf("第 %d 轮删除后容量低于最小容量, 实际为 %d", round, table.Capacity())
+			}
+		}
+		table.Shrink()
+		if table.Capacity() != 64 {
+			t.Errorf("第 %d 轮清空并缩容后期望容量为 64, 实际为 %d", round, table.Capacity())
+		}
+	}
+
+	// 最小容量在派生的表中同样保留
+	filtered := table.Filter(func(key, value any) bool { return true })
+	if filtered.Capacity() != 64 {
+		t.Errorf("Filter 得到的表期望容量为 64, 实际为 %d", filtered.Capacity())
+	}
+}
+
+// TestTinyCapacity 测试 WithMinCapacity 允许容量小于 8 的表正常插入和扩容
+func TestTinyCapacity(t *testing.T) {
+	for _, opts := range [][]Option{
+		{WithMinCapacity(1)},
+		{WithMinCapacity(1), WithProbe(ProbeQuadratic)},
+		{WithMinCapacity(1), WithInPlaceResize()},
+		{WithMinCapacity(1), WithGrowthFactor(1.5)},
+	} {
+		table := NewTable(2, opts...)
+		if table.Capacity() != 2 {
+			t.Fatalf("初始容量期望为 2, 实际为 %d", table.Capacity())
+		}
+
+		table.Insert("a", 1)
+		if table.Capacity() != 2 || table.Find("a") != 1 {
+			t.Errorf("插入第一个键不应扩容, 容量为 %d", table.Capacity())
+		}
+		table.Insert("b", 2)
+		if table.Capacity() <= 2 {
+			t.Errorf("插入第二个键后应扩容, 容量为 %d", table.Capacity())
+		}
+
+		for i := 0; i < 50; i++ {
+			table.Insert(i, i)
+		}
+		for i := 0; i < 50; i++ {
+			if table.Find(i) != i {
+				t.Errorf("扩容后 key=%d 查找错误", i)
+			}
+			table.Delete(i)
+		}
+		table.Delete("a")
+		table.Delete("b")
+		table.Shrink()
+		if table.Capacity() != 1 {
+			t.Errorf("清空后缩容期望容量为 1, 实际为 %d", table.Capacity())
+		}
+		table.Insert("c", 3)
+		if table.Find("c") != 3 {
+			t.Errorf("容量为 1 时插入后查找错误")
+		}
+		if err := table.Validate(); err != nil {
+			t.Errorf("校验失败: %v", err)
+		}
+	}
+}
+
+// TestTypedHash 测试为 int 注册的哈希函数只用于 int 键
+func TestTypedHash(t *testing.T) {
+	calls := 0
+	hashers := map[reflect.Type]func(any) uint64{
+		reflect.TypeOf(0): func(key any) uint64 {
+			calls++
+			return mix64(uint64(key.(int)))
+		},
+	}
+	table := NewTable(8, WithTypedHash(hashers))
+	// 之后修改传入的 map 不影响表
+	delete(hashers, reflect.TypeOf(0))
+
+	if table.hash(42) != mix64(42) || calls != 1 {
+		t.Errorf("int 键应使用注册的哈希函数, 调用次数 %d", calls)
+	}
+	table.hash("42")
+	table.hash(int64(42))
+	if calls != 1 {
+		t.Errorf("其它类型的键不应使用注册的哈希函数, 调用次数 %d", calls)
+	}
+
+	for i := 0; i < 100; i++ {
+		table.Insert(i, i)
+		table.Insert(fmt.Sprint(i), i)
+	}
+	for i := 0; i < 100; i++ {
+		if table.Find(i) != i {
+			t.Errorf("查找 %d 失败", i)
+		}
+		if v, ok := table.FindString(fmt.Sprint(i)); !ok || v != i {
+			t.Errorf("查找 %q 失败, 返回 %v", fmt.Sprint(i), v)
+		}
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+
+	// 派生的表保留注册的哈希函数
+	filtered := table.Filter(func(key, value any) bool { return true })
+	before := calls
+	filtered.Find(1)
+	if calls != before+1 {
+		t.Errorf("Filter 得到的表应保留注册的哈希函数")
+	}
+}
+
+// TestDuplicateBatchPolicy 测试三种处理同一批中重复键的方式
+func TestDuplicateBatchPolicy(t *testing.T) {
+	keys := []any{"a", "b", "a", "c"}
+	vals := []any{1, 2, 3, 4}
+
+	overwrite := NewTable(8)
+	if err := overwrite.InsertBatch(keys, vals); err != nil {
+		t.Fatalf("默认方式不应报错, 实际为 %v", err)
+	}
+	if v := overwrite.Find("a"); v != 3 {
+		t.Errorf("默认方式期望后出现的值覆盖, a=%v", v)
+	}
+
+	strict := NewTable(8, WithDuplicateBatchPolicy(DuplicateError))
+	strict.Insert("x", 0)
+	err := strict.InsertBatch(keys, vals)
+	if !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("DuplicateError 期望返回 ErrDuplicateKey, 实际为 %v", err)
+	}
+	if strict.Size() != 1 || strict.Find("b") != nil {
+		t.Errorf("返回错误时表不应被修改, size=%d", strict.Size())
+	}
+	// 与表中已有的键相同不算重复
+	if err := strict.InsertBatch([]any{"x", "y"}, []any{1, 2}); err != nil || strict.Find("x") != 1 {
+		t.Errorf("与已有键相同时应更新, 错误为 %v", err)
+	}
+
+	first := NewTable(8, WithDuplicateBatchPolicy(DuplicateKeepFirst))
+	if err := first.InsertBatch(keys, vals); err != nil {
+		t.Fatalf("DuplicateKeepFirst 不应报错, 实际为 %v", err)
+	}
+	if v := first.Find("a"); v != 1 {
+		t.Errorf("DuplicateKeepFirst 期望保留第一次出现的值, a=%v", v)
+	}
+	if first.Size() != 3 || first.Find("c") != 4 {
+		t.Errorf("其它键应正常插入, size=%d", first.Size())
+	}
+
+	// 自定义比较函数下等价的键同样视为重复
+	custom := NewTable(8,
+		WithDuplicateBatchPolicy(DuplicateError),
+		WithKeyEqual(reflect.DeepEqual),
+		func(st *Table) { st.hashFn = func(key any) uint64 { return uint64(len(fmt.Sprint(key))) } },
+	)
+	err = custom.InsertBatch([]any{[]int{1}, []int{1}}, []any{1, 2})
+	if !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("等价的切片键期望返回 ErrDuplicateKey, 实际为 %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("未知的处理方式应 panic")
+		}
+	}()
+	WithDuplicateBatchPolicy(DuplicatePolicy(100))
+}
+
+// TestMaxProbe 测试探测距离超过上限时提前调整
+func TestMaxProbe(t *testing.T) {
+	// 自定义的恒定哈希无法换种子, 负载过半后提前扩容
+	constant := func(opts ...Option) *Table {
+		table := NewTable(64, opts...)
+		table.hashFn = func(any) uint64 { return 0 }
+		for i := 0; i < 100; i++ {
+			table.Insert(i, i)
+		}
+		return table
+	}
+	plain, limited := constant(), constant(WithMaxProbe(4))
+	if limited.ResizeCount() <= plain.ResizeCount() {
+		t.Errorf("超过探测上限后期望提前扩容, 扩容次数 %d <= %d", limited.ResizeCount(), plain.ResizeCount())
+	}
+	// 负载不足一半时不扩容, 容量不会无限增长
+	if limited.Capacity() > 2*plain.Capacity() {
+		t.Errorf("提前扩容后容量 %d 过大", limited.Capacity())
+	}
+
+	// 默认哈希下构造在种子 1 时落入同一个槽位的键
+	probe := NewTable(64, WithSeed(1))
+	var keys []int
+	for k := 0; len(keys) < 20; k++ {
+		if probe.getIndex(k) == 0 {
+			keys = append(keys, k)
+		}
+	}
+
+	maxDistance := func(table *Table) int {
+		most := 0
+		for _, k := range keys {
+			d, ok := table.ProbeDistance(k)
+			if !ok {
+				t.Fatalf("查找 %d 失败", k)
+			}
+			most = max(most, d)
+		}
+		return most
+	}
+
+	seeded := NewTable(64, WithSeed(1))
+	reseeded := NewTable(64, WithSeed(1), WithMaxProbe(3))
+	for _, k := range keys {
+		seeded.Insert(k, k)
+		reseeded.Insert(k, k)
+	}
+	if maxDistance(seeded) != len(keys)-1 {
+		t.Errorf("未限制时期望最大探测距离为 %d, 实际为 %d", len(keys)-1, maxDistance(seeded))
+	}
+	if reseeded.seed == 1 {
+		t.Errorf("超过探测上限后期望更换种子")
+	}
+	if d := maxDistance(reseeded); d >= len(keys)/2 {
+		t.Errorf("更换种子后最大探测距离期望明显缩短, 实际为 %d", d)
+	}
+	if reseeded.Capacity() != 64 {
+		t.Errorf("更换种子不应改变容量, 实际为 %d", reseeded.Capacity())
+	}
+	if err := reseeded.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+
+	// 普通键在高负载下偶尔出现长探测, 每次扩容之间最多换一次种子
+	if !invariantChecks {
+		natural := NewTable(8, WithMaxProbe(8))
+		reseeds, seed := 0, natural.seed
+		for i := 0; i < 20000; i++ {
+			natural.Insert(fmt.Sprintf("k%d", i), i)
+			if natural.seed != seed {
+				reseeds, seed = reseeds+1, natural.seed
+			}
+		}
+		if reseeds > natural.ResizeCount()+1 {
+			t.Errorf("换种子 %d 次, 超过扩容次数 %d 加 1", reseeds, natural.ResizeCount())
+		}
+		if natural.Size() != 20000 {
+			t.Errorf("期望 size=20000, 实际为 %d", natural.Size())
+		}
+	}
+
+	// 拉链法不参与调整, 链表保持完整
+	chained := NewTable(1024, WithChaining(), WithMaxProbe(1))
+	chained.hashFn = func(any) uint64 { return 0 }
+	for i := 0; i < 6; i++ {
+		chained.Insert(i, i)
+	}
+	if err := chained.Validate(); err != nil {
+		t.Errorf("拉链法校验失败: %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		if v := chained.Find(i); v != i {
+			t.Errorf("拉链法查找 %d 失败, 返回 %v", i, v)
+		}
+	}
+	if chained.Size() != 6 || chained.Capacity() != 1024 {
+		t.Errorf("拉链法期望 size=6 且容量不变, 实际为 %d, %d", chained.Size(), chained.Capacity())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("WithMaxProbe(0) 应 panic")
+		}
+	}()
+	WithMaxProbe(0)
+}
@@ -0,0 +1,44 @@
+package table
+
+import "container/list"
+
+// forEach 依次对每个未过期的槽位调用 fn，fn 返回 false 时停止
+// 开启 WithInsertionOrder 时按插入顺序遍历，否则按槽位顺序遍历
+func (st *Table) forEach(fn func(slot int) bool) {
+	now := st.expireNow()
+	if st.order == nil {
+		for i := 0; i < st.capacity; i++ {
+			if st.live(i, now) && !fn(i) {
+				return
+			}
+		}
+		return
+	}
+
+	for e := st.order.Front(); e != nil; e = e.Next() {
+		slot := st.elementSlot(e, st.side.order)
+		if slot >= 0 && st.live(slot, now) && !fn(slot) {
+			return
+		}
+	}
+}
+
+// cloneOrder 复制插入顺序链表并更新条目中的节点
+// 与快照共享的链表不能再修改，在复制底层数组后调用
+func (st *Table) cloneOrder() {
+	if st.order == nil {
+		return
+	}
+
+	clone := list.New()
+	nodes := make(map[*list.Element]*list.Element, st.order.Len())
+	for e := st.order.Front(); e != nil; e = e.Next() {
+		nodes[e] = clone.PushBack(e.Value)
+	}
+	for i, node := range st.side.order {
+		if node != nil {
+			st.side.order[i] = nodes[node]
+		}
+	}
+	st.order = clone
+}
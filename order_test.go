@@ -0,0 +1,86 @@
+package table
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestInsertionOrder 测试 Keys 按插入顺序返回
+func TestInsertionOrder(t *testing.T) {
+	table := NewTable(8, WithInsertionOrder())
+
+	var want []any
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key-%d", (i*37)%100)
+		table.Insert(key, i)
+		want = append(want, key)
+	}
+	if got := table.Keys(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Keys 应按插入顺序返回, 实际为 %v", got)
+	}
+
+	// 更新不改变顺序
+	table.Insert(want[0], "updated")
+	if got := table.Keys(); got[0] != want[0] {
+		t.Errorf("更新后 %v 应仍在最前, 实际为 %v", want[0], got[0])
+	}
+
+	// 删除后重新插入排到最后
+	table.Delete(want[1])
+	table.Insert(want[1], "again")
+	want = append(append([]any{want[0]}, want[2:]...), want[1])
+	if got := table.Keys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("重新插入的键应排到最后, 实际为 %v", got)
+	}
+
+	var ranged []any
+	table.Range(func(key, value any) bool {
+		ranged = append(ranged, key)
+		return len(ranged) < 3
+	})
+	if !reflect.DeepEqual(ranged, want[:3]) {
+		t.Errorf("Range 应按插入顺序并在返回 false 时停止, 实际为 %v", ranged)
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+// TestInsertionOrderSnapshot 测试原表的修改不影响快照的遍历顺序
+func TestInsertionOrderSnapshot(t *testing.T) {
+	table := NewTable(8, WithInsertionOrder())
+	table.Insert("a", 1)
+	table.Insert("b", 2)
+	table.Insert("c", 3)
+
+	snap := table.Snapshot()
+	table.Delete("a")
+	table.Insert("a", 4)
+	for i := 0; i < 20; i++ {
+		table.Insert(i, i)
+	}
+
+	if got := snap.Keys(); !reflect.DeepEqual(got, []any{"a", "b", "c"}) {
+		t.Errorf("快照的遍历顺序不应改变, 实际为 %v", got)
+	}
+	if got := table.Keys()[:4]; !reflect.DeepEqual(got, []any{"b", "c", "a", 0}) {
+		t.Errorf("原表的遍历顺序错误, 实际为 %v", got)
+	}
+}
+
+// TestInsertionOrderUnreachableKey 测试无法按键查找的键（NaN、无法比较的键）同样按插入顺序遍历
+func TestInsertionOrderUnreachableKey(t *testing.T) {
+	table := NewTable(8, WithInsertionOrder())
+	table.Insert(math.NaN(), 1)
+	table.Insert("a", 2)
+	table.Insert([]int{1}, 3)
+
+	if keys := table.Keys(); len(keys) != table.Size() {
+		t.Fatalf("Keys 长度 %d 应等于 Size %d", len(keys), table.Size())
+	}
+	if values := fmt.Sprint(table.Values()); values != "[1 2 3]" {
+		t.Errorf("期望按插入顺序返回 [1 2 3], 实际为 %s", values)
+	}
+}
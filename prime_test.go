@@ -0,0 +1,69 @@
+package table
+
+import "testing"
+
+// TestNextPrime 测试 nextPrime 返回不小于 n 的最小质数
+func TestNextPrime(t *testing.T) {
+	cases := map[int]int{0: 2, 2: 2, 3: 3, 4: 5, 8: 11, 14: 17, 24: 29, 1024: 1031, 7919: 7919}
+	for n, want := range cases {
+		if got := nextPrime(n); got != want {
+			t.Errorf("nextPrime(%d) 期望为 %d, 实际为 %d", n, want, got)
+		}
+	}
+}
+
+// TestPrimeCapacity 测试开启后扩缩容得到的容量都是质数
+func TestPrimeCapacity(t *testing.T) {
+	skipUnderInvariantChecks(t)
+	table := NewTable(8, WithPrimeCapacity())
+	capacities := []int{table.Capacity()}
+	table.OnResize(func(oldCap, newCap int) {
+		capacities = append(capacities, newCap)
+	})
+
+	for i := 0; i < 10000; i++ {
+		table.Insert(i, i)
+	}
+	for i := 0; i < 9900; i++ {
+		table.Delete(i)
+	}
+	table.Shrink()
+	table.Expand(5000)
+
+	for _, c := range capacities {
+		if !isPrime(c) {
+			t.Errorf("容量 %d 不是质数, 容量变化为 %v", c, capacities)
+		}
+	}
+	for i := 9900; i < 10000; i++ {
+		if table.Find(i) != i {
+			t.Errorf("key=%d 查找失败", i)
+		}
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+// TestPrimeCapacityDistribution 测试哈希值都是 2 的幂的倍数时，质数容量的聚集明显少于 2 的幂容量
+func TestPrimeCapacityDistribution(t *testing.T) {
+	probes := func(opts ...Option) int {
+		table := NewTable(1024, opts...)
+		table.hashFn = func(key any) uint64 { return uint64(key.(int)) * 64 }
+		for i := 0; i < 500; i++ {
+			table.Insert(i, i)
+		}
+
+		total := 0
+		for i := 0; i < 500; i++ {
+			d, _ := table.ProbeDistance(i)
+			total += d
+		}
+		return total
+	}
+
+	pow2, prime := probes(), probes(WithPrimeCapacity())
+	if prime*10 > pow2 {
+		t.Errorf("质数容量的总探测步数 %d 应远少于 2 的幂容量的 %d", prime, pow2)
+	}
+}
@@ -0,0 +1,95 @@
+package table
+
+// Merge 把 other 中的所有键值对合并到当前表
+// 当键已存在时，overwrite 为 true 则用 other 的值覆盖，否则保留当前值
+func (st *Table) Merge(other *Table, overwrite bool) {
+	st.checkWritable()
+
+	if other == nil || other == st {
+		return
+	}
+
+	// 按合并后的最大规模一次性扩容，避免逐个插入时反复扩容
+	for float64(st.size+other.size) > float64(st.capacity)*st.loadFactor {
+		st.resize(st.nextCapacity())
+	}
+
+	now := other.expireNow()
+	for i := 0; i < other.capacity; i++ {
+		if !other.live(i, now) {
+			continue
+		}
+
+		key := other.entries[i].key
+		if !overwrite && st.lookup(key) >= 0 {
+			continue
+		}
+		st.Insert(key, other.valueAt(i))
+	}
+}
+
+// Intersect 返回一个新表，包含同时存在于当前表和 other 中的键，值取自当前表
+func (st *Table) Intersect(other *Table) *Table {
+	res := st.newLike(8)
+	if other == nil {
+		return res
+	}
+
+	now := st.expireNow()
+	for i := 0; i < st.capacity; i++ {
+		if !st.live(i, now) {
+			continue
+		}
+		if other.lookup(st.entries[i].key) >= 0 {
+			res.Insert(st.entries[i].key, st.valueAt(i))
+		}
+	}
+	return res
+}
+
+// Difference 返回一个新表，包含存在于当前表但不存在于 other 中的键
+func (st *Table) Difference(other *Table) *Table {
+	res := st.newLike(8)
+
+	now := st.expireNow()
+	for i := 0; i < st.capacity; i++ {
+		if !st.live(i, now) {
+			continue
+		}
+		if other != nil && other.lookup(st.entries[i].key) >= 0 {
+			continue
+		}
+		res.Insert(st.entries[i].key, st.valueAt(i))
+	}
+	return res
+}
+
+// Equal 判断两个表是否包含相同的键集合，且每个键对应的值相等
+// valueEq 为 nil 时使用 == 比较值，无法比较的值（例如切片、map，包括 WithMultiValue 存储的值列表）视为不相等；
+// 容量和删除标记的差异不影响结果
+func (st *Table) Equal(other *Table, valueEq func(a, b any) bool) bool {
+	if other == nil {
+		return false
+	}
+	if st == other {
+		return true
+	}
+	if st.sizeAt(st.expireNow()) != other.sizeAt(other.expireNow()) {
+		return false
+	}
+	if valueEq == nil {
+		valueEq = valuesEqual
+	}
+
+	now := st.expireNow()
+	for i := 0; i < st.capacity; i++ {
+		if !st.live(i, now) {
+			continue
+		}
+		slot := other.lookup(st.entries[i].key)
+		if slot < 0 || !valueEq(st.valueAt(i), other.valueAt(slot)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,203 @@
+package table
+
+import (
+	"fmt"
+	"testing"
+)
+
+func buildTable(from, to int) *Table {
+	table := NewTable(8)
+	for i := from; i < to; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	return table
+}
+
+// TestMergeDisjoint 测试合并键集合不相交的两个表
+func TestMergeDisjoint(t *testing.T) {
+	for _, overwrite := range []bool{true, false} {
+		a := buildTable(0, 50)
+		b := buildTable(50, 100)
+
+		a.Merge(b, overwrite)
+
+		if a.Size() != 100 {
+			t.Errorf("overwrite=%v, 合并后 size 期望=100, 实际=%d", overwrite, a.Size())
+		}
+		for i := 0; i < 100; i++ {
+			if v := a.Find(fmt.Sprintf("key-%d", i)); v != i {
+				t.Errorf("overwrite=%v, 合并后查找 key-%d 失败, 返回 %v", overwrite, i, v)
+			}
+		}
+		if b.Size() != 50 {
+			t.Errorf("合并不应修改 other, size 期望=50, 实际=%d", b.Size())
+		}
+	}
+}
+
+// TestMergeOverlapping 测试合并键集合有重叠的两个表
+func TestMergeOverlapping(t *testing.T) {
+	for _, overwrite := range []bool{true, false} {
+		a := buildTable(0, 60)
+		b := NewTable(8)
+		for i := 40; i < 100; i++ {
+			b.Insert(fmt.Sprintf("key-%d", i), i*10)
+		}
+
+		a.Merge(b, overwrite)
+
+		if a.Size() != 100 {
+			t.Errorf("overwrite=%v, 合并后 size 期望=100, 实际=%d", overwrite, a.Size())
+		}
+		for i := 0; i < 100; i++ {
+			expected := i
+			if i >= 60 || (i >= 40 && overwrite) {
+				expected = i * 10
+			}
+			if v := a.Find(fmt.Sprintf("key-%d", i)); v != expected {
+				t.Errorf("overwrite=%v, key-%d 期望=%d, 实际=%v", overwrite, i, expected, v)
+			}
+		}
+	}
+}
+
+// TestMergeStoredNil 测试 overwrite 为 false 时不会覆盖值为 nil 的已有键
+func TestMergeStoredNil(t *testing.T) {
+	a := NewTable(8)
+	a.Insert("k", nil)
+	b := NewTable(8)
+	b.Insert("k", 1)
+
+	a.Merge(b, false)
+	if v := a.Find("k"); v != nil {
+		t.Errorf("overwrite=false 时不应覆盖已有键, 返回 %v", v)
+	}
+
+	a.Merge(a, true)
+	if a.Size() != 1 {
+		t.Errorf("与自身合并后 size 期望=1, 实际=%d", a.Size())
+	}
+}
+
+// TestIntersectAndDifference 测试交集与差集
+func TestIntersectAndDifference(t *testing.T) {
+	a := buildTable(0, 60)
+	b := NewTable(8)
+	for i := 40; i < 100; i++ {
+		b.Insert(fmt.Sprintf("key-%d", i), i*10)
+	}
+
+	inter := a.Intersect(b)
+	if inter.Size() != 20 {
+		t.Errorf("交集 size 期望=20, 实际=%d", inter.Size())
+	}
+	for i := 40; i < 60; i++ {
+		if v := inter.Find(fmt.Sprintf("key-%d", i)); v != i {
+			t.Errorf("交集应保留当前表的值, key-%d 期望=%d, 实际=%v", i, i, v)
+		}
+	}
+
+	diff := a.Difference(b)
+	if diff.Size() != 40 {
+		t.Errorf("差集 size 期望=40, 实际=%d", diff.Size())
+	}
+	for i := 0; i < 40; i++ {
+		if v := diff.Find(fmt.Sprintf("key-%d", i)); v != i {
+			t.Errorf("差集查找 key-%d 失败, 返回 %v", i, v)
+		}
+	}
+	if v := diff.Find("key-50"); v != nil {
+		t.Errorf("差集不应包含 key-50, 返回 %v", v)
+	}
+}
+
+// TestSetOperationsEdgeCases 测试空表与完全重叠的表
+func TestSetOperationsEdgeCases(t *testing.T) {
+	empty := NewTable(8)
+	full := buildTable(0, 20)
+	same := buildTable(0, 20)
+
+	cases := []struct {
+		name      string
+		result    *Table
+		expectLen int
+	}{
+		{"空表 ∩ 非空表", empty.Intersect(full), 0},
+		{"非空表 ∩ 空表", full.Intersect(empty), 0},
+		{"空表 - 非空表", empty.Difference(full), 0},
+		{"非空表 - 空表", full.Difference(empty), 20},
+		{"空表 ∩ 空表", empty.Intersect(empty), 0},
+		{"空表 - 空表", empty.Difference(empty), 0},
+		{"完全重叠 ∩", full.Intersect(same), 20},
+		{"完全重叠 -", full.Difference(same), 0},
+		{"自身 ∩ 自身", full.Intersect(full), 20},
+		{"自身 - 自身", full.Difference(full), 0},
+	}
+
+	for _, c := range cases {
+		if c.result.Size() != c.expectLen {
+			t.Errorf("%s: size 期望=%d, 实际=%d", c.name, c.expectLen, c.result.Size())
+		}
+	}
+}
+
+// TestEqual 测试不同插入顺序、容量和删除标记下的相等判断
+func TestEqual(t *testing.T) {
+	a := NewTable(8)
+	for i := 0; i < 50; i++ {
+		a.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+
+	b := NewTable(64)
+	for i := 49; i >= 0; i-- {
+		b.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	b.Insert("tmp", 0)
+	b.Delete("tmp")
+
+	if !a.Equal(b, nil) || !b.Equal(a, nil) {
+		t.Errorf("以不同顺序插入的表应相等")
+	}
+
+	b.Insert("key-0", 100)
+	if a.Equal(b, nil) {
+		t.Errorf("值不同的表不应相等")
+	}
+
+	anyEq := func(x, y any) bool { return true }
+	if !a.Equal(b, anyEq) {
+		t.Errorf("自定义 valueEq 总是返回 true 时应相等")
+	}
+
+	b.Insert("extra", 1)
+	if a.Equal(b, anyEq) {
+		t.Errorf("键集合不同的表不应相等")
+	}
+
+	if a.Equal(nil, nil) {
+		t.Errorf("与 nil 比较不应相等")
+	}
+	if !NewTable(8).Equal(NewTable(32), nil) {
+		t.Errorf("两个空表应相等")
+	}
+}
+
+// TestEqualIncomparableValues 测试 valueEq 为 nil 时无法比较的值视为不相等而不是 panic
+func TestEqualIncomparableValues(t *testing.T) {
+	a, b := NewTable(8), NewTable(8)
+	a.Insert("k", []int{1})
+	b.Insert("k", []int{1})
+	if a.Equal(b, nil) {
+		t.Errorf("切片值无法比较, 不应视为相等")
+	}
+
+	multiA, multiB := NewTable(8, WithMultiValue()), NewTable(8, WithMultiValue())
+	multiA.Insert("k", 1)
+	multiB.Insert("k", 1)
+	if multiA.Equal(multiB, nil) {
+		t.Errorf("值列表无法用 == 比较, 不应视为相等")
+	}
+	if !multiA.Equal(multiB, func(x, y any) bool { return fmt.Sprint(x) == fmt.Sprint(y) }) {
+		t.Errorf("自定义 valueEq 时值列表相同的表应相等")
+	}
+}
@@ -0,0 +1,166 @@
+package table
+
+import (
+	"container/list"
+	"slices"
+	"unsafe"
+)
+
+// sideArrays 与 entries 平行的附加数组，每个数组只在开启对应功能后分配，未开启时为 nil
+// 默认配置下每个槽位只占用 Entry 中的键和值，可选功能的数据不增加其它表的内存
+type sideArrays struct {
+	// 过期时间（UnixNano），0 表示永不过期；第一次插入带过期时间的键值对时分配
+	expireAt []int64
+	// 插入时间和最近访问时间（UnixNano），开启 WithTimestamps 时分配
+	createdAt  []int64
+	accessedAt []int64
+	// 在最近使用链表中的节点，开启 WithMaxEntries 时分配
+	lru []*list.Element
+	// 在插入顺序链表中的节点，开启 WithInsertionOrder 时分配
+	order []*list.Element
+	// 拉链法下同一个桶或空闲链表中下一个条目的索引加 1，0 表示链表结束；由 initChains 分配
+	next []int
+}
+
+// allocSide 为当前配置分配长度为 n 的附加数组，next 由 initChains 分配
+// 过期时间数组在第一次插入带过期时间的键值对时才分配，已分配过的表重建时继续保留
+func (st *Table) allocSide(n int) sideArrays {
+	var s sideArrays
+	if st.side.expireAt != nil {
+		s.expireAt = make([]int64, n)
+	}
+	if st.timestamps {
+		s.createdAt = make([]int64, n)
+		s.accessedAt = make([]int64, n)
+	}
+	if st.lru != nil {
+		s.lru = make([]*list.Element, n)
+	}
+	if st.order != nil {
+		s.order = make([]*list.Element, n)
+	}
+	return s
+}
+
+// clone 复制所有已分配的数组
+func (s sideArrays) clone() sideArrays {
+	return sideArrays{
+		expireAt:   slices.Clone(s.expireAt),
+		createdAt:  slices.Clone(s.createdAt),
+		accessedAt: slices.Clone(s.accessedAt),
+		lru:        slices.Clone(s.lru),
+		order:      slices.Clone(s.order),
+		next:       slices.Clone(s.next),
+	}
+}
+
+// copySlot 把 from 中第 src 个槽位的附加数据复制到第 dst 个槽位，不包括拉链法的链接
+func (s *sideArrays) copySlot(dst int, from *sideArrays, src int) {
+	if s.expireAt != nil {
+		s.expireAt[dst] = from.expireAt[src]
+	}
+	if s.createdAt != nil {
+		s.createdAt[dst] = from.createdAt[src]
+		s.accessedAt[dst] = from.accessedAt[src]
+	}
+	if s.lru != nil {
+		s.lru[dst] = from.lru[src]
+	}
+	if s.order != nil {
+		s.order[dst] = from.order[src]
+	}
+}
+
+// swapSlots 交换两个槽位的附加数据，不包括拉链法的链接
+func (s *sideArrays) swapSlots(i, j int) {
+	swap(s.expireAt, i, j)
+	swap(s.createdAt, i, j)
+	swap(s.accessedAt, i, j)
+	swap(s.lru, i, j)
+	swap(s.order, i, j)
+}
+
+// clearSlot 清空一个槽位的附加数据，不包括拉链法的链接
+func (s *sideArrays) clearSlot(i int) {
+	s.clearRange(i, i+1)
+}
+
+// clearRange 清空 [from, to) 范围内槽位的附加数据，不包括拉链法的链接
+func (s *sideArrays) clearRange(from, to int) {
+	clearSpan(s.expireAt, from, to)
+	clearSpan(s.createdAt, from, to)
+	clearSpan(s.accessedAt, from, to)
+	clearSpan(s.lru, from, to)
+	clearSpan(s.order, from, to)
+}
+
+// resize 把已分配的数组调整为长度 n，只在底层数组容量不足时重新分配，不清空新增的槽位
+func (s *sideArrays) resize(n int) {
+	s.expireAt = resizeSpan(s.expireAt, n)
+	s.createdAt = resizeSpan(s.createdAt, n)
+	s.accessedAt = resizeSpan(s.accessedAt, n)
+	s.lru = resizeSpan(s.lru, n)
+	s.order = resizeSpan(s.order, n)
+}
+
+// bytes 返回已分配的数组占用的字节数
+func (s sideArrays) bytes() int {
+	return (cap(s.expireAt)+cap(s.createdAt)+cap(s.accessedAt))*8 +
+		(cap(s.lru)+cap(s.order))*int(unsafe.Sizeof((*list.Element)(nil))) + cap(s.next)*int(unsafe.Sizeof(0))
+}
+
+func swap[T any](a []T, i, j int) {
+	if a != nil {
+		a[i], a[j] = a[j], a[i]
+	}
+}
+
+func clearSpan[T any](a []T, from, to int) {
+	if a != nil {
+		clear(a[from:to])
+	}
+}
+
+func resizeSpan[T any](a []T, n int) []T {
+	if a == nil {
+		return nil
+	}
+	if n > cap(a) {
+		a = slices.Grow(a, n-len(a))
+	}
+	return a[:n]
+}
+
+// moveSlot 把 src 槽位的条目、元数据和附加数据移动到 dst，并清空 src，不改变拉链法的链接
+func (st *Table) moveSlot(dst, src int) {
+	st.entries[dst], st.metas[dst] = st.entries[src], st.metas[src]
+	st.side.copySlot(dst, &st.side, src)
+	st.entries[src], st.metas[src] = Entry{}, metaEmpty
+	st.side.clearSlot(src)
+}
+
+// swapSlots 交换两个槽位的条目、元数据和附加数据，不改变拉链法的链接
+func (st *Table) swapSlots(i, j int) {
+	st.entries[i], st.entries[j] = st.entries[j], st.entries[i]
+	st.metas[i], st.metas[j] = st.metas[j], st.metas[i]
+	st.side.swapSlots(i, j)
+}
+
+// expireAtSlot 返回槽位的过期时间，没有插入过带过期时间的键值对时为 0
+func (st *Table) expireAtSlot(slot int) int64 {
+	if st.side.expireAt == nil {
+		return 0
+	}
+	return st.side.expireAt[slot]
+}
+
+// setExpireAt 设置槽位的过期时间，第一次设置非 0 的过期时间时分配过期时间数组
+func (st *Table) setExpireAt(slot int, expireAt int64) {
+	if st.side.expireAt == nil {
+		if expireAt == 0 {
+			return
+		}
+		st.side.expireAt = make([]int64, len(st.entries))
+	}
+	st.side.expireAt[slot] = expireAt
+}
@@ -0,0 +1,70 @@
+package table
+
+import (
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// TestSideArraysAllocation 测试默认配置下条目只包含键和值，附加数组只在开启对应功能时分配
+func TestSideArraysAllocation(t *testing.T) {
+	if size := unsafe.Sizeof(Entry{}); size != 2*unsafe.Sizeof(any(nil)) {
+		t.Errorf("Entry 应只包含键和值, 实际大小为 %d", size)
+	}
+
+	table := NewTable(8)
+	for i := 0; i < 100; i++ {
+		table.Insert(i, i)
+	}
+	if s := table.side; s.expireAt != nil || s.createdAt != nil || s.lru != nil || s.order != nil || s.next != nil {
+		t.Errorf("默认配置不应分配附加数组")
+	}
+
+	table.InsertWithTTL("ttl", 1, time.Minute)
+	if len(table.side.expireAt) != table.Capacity() {
+		t.Errorf("插入带过期时间的键后应分配过期时间数组")
+	}
+}
+
+// TestSideArraysFollowEntries 测试各种重排方式移动条目时附加数据随之移动
+func TestSideArraysFollowEntries(t *testing.T) {
+	backends := map[string][]Option{
+		"默认":   nil,
+		"原地扩容": {WithInPlaceResize()},
+		"拉链法":  {WithChaining()},
+		"布谷鸟":  {WithCuckoo()},
+	}
+	for name, opts := range backends {
+		clock := newFakeClock()
+		opts = append(opts, WithClock(clock.Now), WithTimestamps(), WithInsertionOrder(), WithMaxEntries(1000))
+		table := NewTable(8, opts...)
+		for i := 0; i < 300; i++ {
+			table.InsertWithTTL(i, i, time.Duration(i+1)*time.Second)
+			clock.Advance(time.Millisecond)
+		}
+		for i := 0; i < 300; i += 2 {
+			table.Delete(i)
+		}
+		table.Shrink()
+
+		clock.Advance(200 * time.Second)
+		if err := table.Validate(); err != nil {
+			t.Fatalf("%s: 校验失败: %v", name, err)
+		}
+		keys := table.Keys()
+		if len(keys) != 50 {
+			t.Errorf("%s: 期望剩余 50 个未过期的键, 实际为 %d", name, len(keys))
+		}
+		for j, k := range keys {
+			i := 2*j + 201
+			if k != i {
+				t.Errorf("%s: 第 %d 个键期望为 %d, 实际为 %v", name, j, i, k)
+				break
+			}
+			if age, ok := table.Age(i); !ok || age != time.Duration(300-i)*time.Millisecond+200*time.Second {
+				t.Errorf("%s: 键 %d 的插入时间错误, 返回 %v", name, i, age)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package table
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+)
+
+// record 单条键值对的序列化形式
+type record struct {
+	Key   any
+	Value any
+}
+
+// WriteTo 以流式方式把所有键值对写入 w，返回写入的字节数
+//
+// 格式：8 字节大端序的条目数，随后每个条目为 4 字节大端序长度前缀 + gob 编码的键值对。
+// 键和值的具体类型需要能被 gob 编码，自定义类型需要事先调用 gob.Register 注册。
+func (st *Table) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	var header [8]byte
+	now := st.expireNow()
+	binary.BigEndian.PutUint64(header[:], uint64(st.sizeAt(now)))
+	c, err := w.Write(header[:])
+	n += int64(c)
+	if err != nil {
+		return n, err
+	}
+
+	var buf bytes.Buffer
+	var prefix [4]byte
+	for i := 0; i < st.capacity; i++ {
+		if !st.live(i, now) {
+			continue
+		}
+
+		// 每个条目单独编码，保证条目之间相互独立
+		buf.Reset()
+		rec := record{Key: st.entries[i].key, Value: st.valueAt(i)}
+		if err := gob.NewEncoder(&buf).Encode(&rec); err != nil {
+			return n, fmt.Errorf("encode entry %v: %w", rec.Key, err)
+		}
+
+		binary.BigEndian.PutUint32(prefix[:], uint32(buf.Len()))
+		c, err = w.Write(prefix[:])
+		n += int64(c)
+		if err != nil {
+			return n, err
+		}
+		c, err = w.Write(buf.Bytes())
+		n += int64(c)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// ReadFrom 从 r 中读取 WriteTo 写出的数据并插入到表中，返回读取的字节数
+//
+// 已存在的键会被覆盖
+func (st *Table) ReadFrom(r io.Reader) (int64, error) {
+	if st.frozen {
+		return 0, ErrFrozen
+	}
+
+	var n int64
+	var header [8]byte
+	c, err := io.ReadFull(r, header[:])
+	n += int64(c)
+	if err != nil {
+		return n, err
+	}
+	count := binary.BigEndian.Uint64(header[:])
+
+	var prefix [4]byte
+	var payload bytes.Buffer
+	for i := uint64(0); i < count; i++ {
+		c, err = io.ReadFull(r, prefix[:])
+		n += int64(c)
+		if err != nil {
+			return n, err
+		}
+
+		// 长度前缀来自输入，不能据此预先分配：损坏或恶意的前缀可能要求分配 4 GiB，
+		// 因此按实际读到的数据增长缓冲区
+		length := int64(binary.BigEndian.Uint32(prefix[:]))
+		payload.Reset()
+		c64, err := io.CopyN(&payload, r, length)
+		n += c64
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return n, err
+		}
+
+		var rec record
+		if err := gob.NewDecoder(&payload).Decode(&rec); err != nil {
+			return n, fmt.Errorf("decode entry %d: %w", i, err)
+		}
+		st.Insert(rec.Key, rec.Value)
+	}
+	return n, nil
+}
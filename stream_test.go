@@ -0,0 +1,105 @@
+package table
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+)
+
+// TestWriteToReadFrom 测试通过 bytes.Buffer 流式写出再读回
+func TestWriteToReadFrom(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 100; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	table.Insert(nil, "nil-value")
+	table.Insert(-1, nil)
+	table.Delete("key-0")
+
+	var buf bytes.Buffer
+	written, err := table.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo 发生错误: %v", err)
+	}
+	if written != int64(buf.Len()) {
+		t.Errorf("WriteTo 返回字节数=%d, 实际写入=%d", written, buf.Len())
+	}
+
+	total := buf.Len()
+	restored := NewTable(8)
+	read, err := restored.ReadFrom(&buf)
+	if err != nil {
+		t.Fatalf("ReadFrom 发生错误: %v", err)
+	}
+	if read != int64(total) {
+		t.Errorf("ReadFrom 返回字节数=%d, 期望=%d", read, total)
+	}
+
+	if restored.Size() != table.Size() {
+		t.Errorf("读回后 size 期望=%d, 实际=%d", table.Size(), restored.Size())
+	}
+	for i := 1; i < 100; i++ {
+		if v := restored.Find(fmt.Sprintf("key-%d", i)); v != i {
+			t.Errorf("读回后查找 key-%d 失败, 返回 %v", i, v)
+		}
+	}
+	if v := restored.Find("key-0"); v != nil {
+		t.Errorf("已删除的 key-0 不应被写出, 返回 %v", v)
+	}
+	if v := restored.Find(nil); v != "nil-value" {
+		t.Errorf("nil 键读回失败, 返回 %v", v)
+	}
+}
+
+// TestReadFromTruncated 测试读取被截断的数据时返回错误
+func TestReadFromTruncated(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("apple", 1)
+	table.Insert("banana", 2)
+
+	var buf bytes.Buffer
+	if _, err := table.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo 发生错误: %v", err)
+	}
+
+	data := buf.Bytes()[:buf.Len()-1]
+	restored := NewTable(8)
+	read, err := restored.ReadFrom(bytes.NewReader(data))
+	if err == nil {
+		t.Errorf("读取截断数据应报错, 但未报错")
+	}
+	if read != int64(len(data)) {
+		t.Errorf("ReadFrom 返回字节数=%d, 期望=%d", read, len(data))
+	}
+}
+
+// TestReadFromOversizedPrefix 测试长度前缀远超实际数据时返回错误，且不会按前缀预先分配内存
+func TestReadFromOversizedPrefix(t *testing.T) {
+	var data []byte
+	data = binary.BigEndian.AppendUint64(data, 1)
+	data = binary.BigEndian.AppendUint32(data, 0xFFFFFFFF)
+	data = append(data, "short"...)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	restored := NewTable(8)
+	read, err := restored.ReadFrom(bytes.NewReader(data))
+	runtime.ReadMemStats(&after)
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("期望返回 io.ErrUnexpectedEOF, 实际为 %v", err)
+	}
+	if read != int64(len(data)) {
+		t.Errorf("ReadFrom 返回字节数=%d, 期望=%d", read, len(data))
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("不应按长度前缀预先分配内存, 实际分配了 %d 字节", allocated)
+	}
+	if restored.Size() != 0 {
+		t.Errorf("读取失败时不应插入条目, 实际 size=%d", restored.Size())
+	}
+}
@@ -0,0 +1,60 @@
+package table
+
+// FindString 查找字符串键，第二个返回值表示键是否存在，结果与 FindOK 相同
+// 使用默认哈希和比较方式的开放寻址表直接对字符串计算哈希并比较，查找过程不分配内存；
+// 设置了自定义哈希（包括 WithTypedHash）或比较函数、或者使用拉链法和布谷鸟哈希时退化为 FindOK
+func (st *Table) FindString(key string) (any, bool) {
+	if st.hashFn != nil || st.typedHash != nil || st.keyEqual != nil || st.chaining || st.cuckoo {
+		return st.FindOK(key)
+	}
+
+	slot := st.liveSlot(st.lookupString(key))
+	st.recordLookup(slot >= 0)
+	if slot < 0 {
+		// 只在设置了加载函数时把键转换为 any，未命中的查找同样不分配内存
+		if st.loader != nil {
+			return st.load(key)
+		}
+		return nil, false
+	}
+	st.touch(slot)
+
+	return st.valueAt(slot), true
+}
+
+// InsertString 插入或更新字符串键，与 Insert 相同
+// 字符串键的默认哈希不经过格式化，键本身仍需转换为 any 保存
+func (st *Table) InsertString(key string, value any) {
+	st.put(key, value, 0)
+}
+
+// lookupString 与 lookupSlot 相同，但键直接以字符串比较，避免转换为 any
+// 只用于默认哈希与比较方式下的开放寻址
+func (st *Table) lookupString(key string) int {
+	if st.size == 0 {
+		return -1
+	}
+
+	h := st.stringHash(key)
+	if st.bloom != nil && !st.bloom.mayContain(h) {
+		return -1
+	}
+
+	entries, metas := st.entries, st.metas
+	capacity := len(metas)
+	start := int(h % uint64(capacity))
+	want := metaFull | tagOf(h)
+	limit := st.probeLimit(capacity)
+	for i := 0; i < limit; i++ {
+		slot := st.probeAt(start, i, capacity)
+		switch metas[slot] {
+		case metaEmpty:
+			return -1
+		case want:
+			if k, ok := entries[slot].key.(string); ok && k == key {
+				return slot
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,57 @@
+package table
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestFindString 测试 FindString 与 InsertString 的结果与通用方法一致
+func TestFindString(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"linear":    nil,
+		"quadratic": {WithProbe(ProbeQuadratic)},
+		"chaining":  {WithChaining()},
+		"bloom":     {WithBloomFilter(1000, 0.01)},
+	} {
+		table := NewTable(8, opts...)
+		for i := 0; i < 500; i++ {
+			table.InsertString(fmt.Sprintf("key-%d", i), i)
+		}
+		for i := 0; i < 500; i += 3 {
+			table.Delete(fmt.Sprintf("key-%d", i))
+		}
+		// 格式化后相同的非字符串键不会被当作字符串键
+		table.Insert(7, "int")
+
+		for i := 0; i < 600; i++ {
+			key := fmt.Sprintf("key-%d", i)
+			v1, ok1 := table.FindString(key)
+			v2, ok2 := table.FindOK(key)
+			if v1 != v2 || ok1 != ok2 {
+				t.Errorf("%s: 查找 %s 结果不一致, %v/%v != %v/%v", name, key, v1, ok1, v2, ok2)
+			}
+		}
+		if _, ok := table.FindString("7"); ok {
+			t.Errorf("%s: 字符串 \"7\" 不应找到整数键", name)
+		}
+		if err := table.Validate(); err != nil {
+			t.Errorf("%s: 校验失败: %v", name, err)
+		}
+	}
+}
+
+// TestFindStringNoAlloc 测试 FindString 查找过程不分配内存
+func TestFindStringNoAlloc(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 100; i++ {
+		table.InsertString(fmt.Sprintf("key-%d", i), i)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		table.FindString("key-42")
+		table.FindString("missing")
+	})
+	if allocs != 0 {
+		t.Errorf("FindString 期望不分配内存, 实际每次分配 %v 次", allocs)
+	}
+}
@@ -1,10 +1,29 @@
 package table
 
 import (
+	"container/list"
+	"errors"
 	"fmt"
 	"math"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+	"unsafe"
+)
 
-	"github.com/cespare/xxhash"
+var (
+	// ErrNotComparable 键无法使用 == 比较（例如切片、map），且没有设置自定义的键比较函数
+	ErrNotComparable = errors.New("key is not comparable")
+	// ErrNotInteger 已存在的值不是整数，无法进行计数
+	ErrNotInteger = errors.New("value is not an integer")
+	// ErrFrozen 表已被冻结，不允许修改
+	ErrFrozen = errors.New("table is frozen")
+	// ErrDuplicateKey 同一批中出现了重复的键，见 WithDuplicateBatchPolicy
+	ErrDuplicateKey = errors.New("duplicate key in batch")
+	// ErrCapacityOverflow 请求的容量超过了 maxCapacity，无法分配
+	ErrCapacityOverflow = errors.New("capacity overflow")
 )
 
 // 元数据标记常量
@@ -12,203 +31,1168 @@ const (
 	metaEmpty = 0 // 空槽位
 	metaFull  = 1 // 已占用槽位
 	metaDel   = 2 // 删除的槽位（可复用）
+
+	metaPending = 3 // 原地重排时尚未归位的槽位，只在 rehashInPlace 过程中出现
 )
 
+// tagOf 返回哈希值的标签，存放在已占用槽位元数据的高六位
+// 探测时标签不同的槽位不需要读取 entries 比较键
+func tagOf(h uint64) byte {
+	return byte(h>>56) &^ 0x03
+}
+
+// Entry 表中的一个条目，只存放键和值
+// 过期时间、访问时间、链表节点等可选功能的数据存放在 Table.side 中，只在开启对应功能时分配
 type Entry struct {
-	meta  byte
 	key   any
 	value any
 }
 
+// Key 返回条目的键
+func (e Entry) Key() any {
+	return e.key
+}
+
+// Value 返回条目的值
+func (e Entry) Value() any {
+	return e.value
+}
+
 type Table struct {
 	entries []Entry
+	// 每个槽位的元数据，低两位为状态，已占用时高六位为哈希值的标签
+	// 与 entries 分开连续存放，探测时大多只需扫描这些字节
+	metas []byte
+	// 与 entries 平行、只在开启对应功能时分配的附加数组
+	side sideArrays
 
 	capacity int
 
 	size int
+	// 删除标记的数量，删除标记同样会拉长探测链
+	deleted int
 
 	// 负载因子阈值，超过此阈值就需要扩容
 	loadFactor float64
 
+	// 自定义哈希函数，为 nil 时使用带种子的默认哈希
 	hashFn func(key any) uint64
+	// 默认哈希使用的随机种子，不同的表实例种子不同
+	seed uint64
+
+	// 扩容时容量的增长倍数
+	growthFactor float64
+
+	// 自动缩容阈值，删除后 size 低于 autoShrink * capacity 时自动缩容，0 表示关闭
+	autoShrink float64
+	// 自动整理阈值，删除后墓碑数超过 compactThreshold * capacity 时原容量重建，0 表示关闭
+	compactThreshold float64
+
+	// 累计调整容量的次数
+	resizeCount int
+	// Find 等查找累计命中和未命中的次数，并发读取时以原子操作更新
+	hits   uint64
+	misses uint64
+	// 调整容量后的回调
+	onResize func(oldCap, newCap int)
+	// 调整容量和整理事件的通道，调用 ResizeEvents 后才创建
+	resizeEvents chan ResizeEvent
+
+	// 冲突时的探测方式
+	probe ProbeStrategy
+	// 查找不到键时调用的加载函数，为 nil 时不加载
+	loader func(key any) (any, bool)
+
+	// 插入新键时允许的最大探测距离，超过时换种子重建或扩容，0 表示不限制
+	maxProbe int
+	// 最近一次因探测距离过长换种子时的 resizeCount 加 1，0 表示还没有换过
+	reseedEpoch int
+	// InsertBatch 遇到同一批中重复的键时的处理方式
+	duplicatePolicy DuplicatePolicy
+
+	// 自定义键比较函数，为 nil 时使用 ==
+	keyEqual func(a, b any) bool
+
+	// 插入或更新成功后的回调
+	onInsert func(key, value any)
+	// 删除成功后的回调
+	onDelete func(key, value any)
+
+	// Pop 下一次开始扫描的槽位
+	popCursor int
+
+	// 最小容量，创建和缩容时都不会低于该值
+	minCapacity int
+
+	// 调整容量时是否复用底层数组原地重排
+	inPlace bool
+
+	// 是否已冻结为只读
+	frozen bool
+	// 底层数组是否与快照共享，共享时第一次写入前需要先复制
+	shared bool
+
+	// 按键的类型注册的哈希函数，未注册的类型使用默认哈希
+	typedHash map[reflect.Type]func(any) uint64
+
+	// 时钟，为 nil 时使用 time.Now
+	clock func() time.Time
+	// 是否插入过带过期时间的键值对，没有时读取不需要检查过期
+	hasTTL bool
+	// 是否以弱引用保存指针类型的值
+	weakValues bool
+	// 是否开启多值模式，开启后每个键存放一个值列表
+	multiValue bool
+	// 是否记录插入时间和最近访问时间
+	timestamps bool
+
+	// 最多保存的键值对数量，0 表示不限制
+	maxEntries int
+	// 最近使用链表，从前往后由久到新，节点的值为键
+	lru *list.List
+	// 插入顺序链表，从前往后由早到晚，节点的值为键
+	order *list.List
+
+	// 查找前先判断键是否可能存在的布隆过滤器，nil 表示未开启
+	bloom *bloomFilter
+	// 创建布隆过滤器时预期的键数量与误判率
+	bloomN  int
+	bloomFP float64
+
+	// 容量是否总是取质数
+	primeCapacity bool
+
+	// 是否使用拉链法解决冲突
+	chaining bool
+	// 是否使用布谷鸟哈希解决冲突
+	cuckoo bool
+	// 布谷鸟哈希下存放在备用区中的键值对数量
+	stashed int
+	// 拉链法下每个桶第一个条目的索引加 1，0 表示桶为空
+	heads []int
+	// 拉链法下空闲条目链表头的索引加 1，0 表示没有空闲条目
+	free int
 }
 
-func NewTable(capacity int) *Table {
-	// 初始容量不能太小，避免过度冲突
-	if capacity < 8 {
-		capacity = 8
-	}
+// defaultMinCapacity 默认的最小容量
+const defaultMinCapacity = 8
+
+// maxCapacity 表容量的上限，保证 entries 数组占用的字节数不会溢出 int
+// 运行时单次分配的上限更低（64 位平台为 2^48 字节），超出时扩容同样以 ErrCapacityOverflow 失败
+const maxCapacity = math.MaxInt / int(unsafe.Sizeof(Entry{}))
+
+func NewTable(capacity int, opts ...Option) *Table {
 	// 默认负载因子
 	loadFactor := 0.75
 
 	res := &Table{
-		entries:    make([]Entry, capacity),
-		capacity:   capacity,
-		size:       0,
-		loadFactor: loadFactor,
-		hashFn: func(k any) uint64 {
-			return xxhash.Sum64String(fmt.Sprintf("%v", k))
-		},
+		size:         0,
+		loadFactor:   loadFactor,
+		growthFactor: 2,
+		seed:         randomSeed(),
+		minCapacity:  defaultMinCapacity,
+	}
+	for _, opt := range opts {
+		opt(res)
+	}
+	if res.autoShrink >= res.loadFactor {
+		panic("table: auto shrink threshold must be less than the load factor")
+	}
+
+	// 初始容量不能太小，避免过度冲突
+	capacity = res.normalizeCapacity(capacity)
+	res.entries = make([]Entry, capacity)
+	res.metas = make([]byte, capacity)
+	res.side = res.allocSide(capacity)
+	res.capacity = capacity
+	if res.chaining {
+		res.initChains()
+	}
+	return res
+}
+
+// normalizeCapacity 按最小容量、质数容量等配置调整所需的容量
+func (st *Table) normalizeCapacity(n int) int {
+	if n < st.minCapacity {
+		n = st.minCapacity
+	}
+	if st.cuckoo && n < cuckooMinCapacity {
+		n = cuckooMinCapacity
+	}
+	if st.primeCapacity {
+		n = nextPrime(n)
+	}
+	return n
+}
+
+// NewFromMap 根据内置 map 创建表，按 map 的大小预先分配容量
+func NewFromMap(m map[any]any) *Table {
+	res := NewTable(int(math.Ceil(float64(len(m)) / 0.75)))
+	for k, v := range m {
+		res.Insert(k, v)
+	}
+	return res
+}
+
+// Pair 是一个键值对，用于 NewTableFromPairs
+type Pair struct {
+	Key   any
+	Value any
+}
+
+// NewTableFromPairs 根据键值对创建表，按键值对的数量预先分配容量
+// 重复的键以最后一次出现的值为准
+func NewTableFromPairs(pairs ...Pair) *Table {
+	res := NewTable(CapacityFor(len(pairs), 0.75))
+	for _, p := range pairs {
+		res.Insert(p.Key, p.Value)
 	}
 	return res
 }
 
 // getIndex 返回为键计算的初始槽位索引
 func (st *Table) getIndex(key any) int {
-	return int(st.hashFn(key) % uint64(st.capacity))
+	return st.indexOf(st.hash(key))
+}
+
+// indexOf 返回哈希值对应的初始槽位索引
+// 取模在无符号数上进行，结果小于容量后才转换为 int，任何哈希值（包括最高位为 1 的）都不会得到负的索引
+func (st *Table) indexOf(h uint64) int {
+	return int(h % uint64(st.capacity))
+}
+
+// nextCapacity 按增长倍数计算下一次扩容的容量
+func (st *Table) nextCapacity() int {
+	// 先用浮点数比较，避免超出 int 范围的转换
+	grown := math.Ceil(float64(st.capacity) * st.growthFactor)
+	if grown >= float64(maxCapacity) {
+		return maxCapacity
+	}
+	next := int(grown)
+	if next <= st.capacity {
+		next = st.capacity + 1
+	}
+	return next
+}
+
+// probeLimit 返回在容量为 capacity 的数组中一次查找最多探测的次数
+func (st *Table) probeLimit(capacity int) int {
+	if st.probe == ProbeQuadratic {
+		// 二次探测不保证覆盖所有槽位，探测 capacity 次后再线性扫描一圈兜底
+		return 2 * capacity
+	}
+	return capacity
+}
+
+// probeAt 返回在容量为 capacity 的数组中从 start 出发第 i 次探测的槽位索引
+// 容量由调用方传入，同一次探测的所有索引都按同一个容量计算
+func (st *Table) probeAt(start, i, capacity int) int {
+	if st.probe == ProbeQuadratic && i < capacity {
+		// 三角数偏移 i*(i+1)/2，相比 i+i*i 不会只落在与 start 同奇偶的槽位上
+		offset := uint64(i) * uint64(i+1) / 2
+		return int((uint64(start) + offset) % uint64(capacity))
+	}
+	return (start + i) % capacity
 }
 
-// findSlot 采用开放寻址（这里用线性探测的示例）
-// slotIndex: 初始索引
+// keysEqual 判断两个键是否相等
+// 动态类型无法比较的键（例如切片、map）使用 == 比较会 panic，这里恢复并视为不相等
+func (st *Table) keysEqual(a, b any) (equal bool) {
+	if st.keyEqual != nil {
+		return st.keyEqual(a, b)
+	}
+	defer func() {
+		if recover() != nil {
+			equal = false
+		}
+	}()
+	return a == b
+}
+
+// valuesEqual 使用 == 比较两个值，值无法比较（例如切片、map）时视为不相等而不是 panic
+func valuesEqual(a, b any) (equal bool) {
+	defer func() {
+		if recover() != nil {
+			equal = false
+		}
+	}()
+	return a == b
+}
+
+// findSlot 采用开放寻址，探测方式由 probe 决定（默认线性探测）
+// h: 键的哈希值，决定初始索引和标签
 // key: 用于查找冲突的目标键
 // insertMode: 是否处于插入模式。插入模式下遇到删除标记也可复用。
-func (st *Table) findSlot(slotIndex int, key any, insertMode bool) int {
-	start := slotIndex
-	for {
-		meta := st.entries[slotIndex].meta & 0x03 // 只取低两位
+func (st *Table) findSlot(h uint64, key any, insertMode bool) int {
+	slot, _ := st.probeSlot(h, key, insertMode)
+	return slot
+}
+
+// probeSlot 与 findSlot 相同，同时返回找到槽位时已探测的步数
+func (st *Table) probeSlot(h uint64, key any, insertMode bool) (slot, distance int) {
+	if st.chaining {
+		return st.chainSlot(h, key, insertMode)
+	}
+	if st.cuckoo {
+		return st.cuckooSlot(h, key, insertMode)
+	}
+
+	// 一次探测只读取一次数组和容量，初始索引与之后的探测索引都按同一个容量计算，
+	// 保证所有索引都落在读取到的数组范围内
+	entries, metas := st.entries, st.metas
+	capacity := len(metas)
+	start := int(h % uint64(capacity))
+	want := metaFull | tagOf(h)
+	// 插入模式下遇到的第一个删除标记
+	reuse, reuseDistance := -1, 0
+	limit := st.probeLimit(capacity)
+	for i := 0; i < limit; i++ {
+		slotIndex := st.probeAt(start, i, capacity)
+		meta := metas[slotIndex] & 0x03 // 只取低两位
 
 		// 情况 1：空槽位
 		//  - 查找模式下，如果是空槽位则代表没找到，直接返回该索引
-		//  - 插入模式下，空槽位可以直接插入
+		//  - 插入模式下，键一定不在表中，优先复用之前遇到的删除标记，否则插入此空槽位
 		if meta == metaEmpty {
-			return slotIndex
+			if reuse >= 0 {
+				return reuse, reuseDistance
+			}
+			return slotIndex, i
 		}
 
 		// 情况 2：删除标记
 		//  - 查找模式下，继续探测
-		//  - 插入模式下，可以复用此槽位
-		if meta == metaDel && insertMode {
-			return slotIndex
+		//  - 插入模式下，记录下来以便复用，但键可能在之后的槽位中，需要继续探测
+		if meta == metaDel {
+			if insertMode && reuse < 0 {
+				reuse, reuseDistance = slotIndex, i
+			}
+			continue
 		}
 
-		// 情况 3：已占用槽位，需要比较是否是要找的目标键
-		if meta == metaFull {
-			if st.entries[slotIndex].key == key {
+		// 情况 3：已占用槽位，标签相同时才需要比较是否是要找的目标键
+		if metas[slotIndex] == want {
+			if st.keysEqual(entries[slotIndex].key, key) {
 				// 找到了匹配键，直接返回
-				return slotIndex
+				return slotIndex, i
 			}
 		}
+	}
 
-		// 线性探测：继续往下一个槽位找
-		slotIndex = (slotIndex + 1) % st.capacity
-
-		// 如果绕了一圈还没找到，说明表满了或冲突严重（理应在插入前扩容）
-		if slotIndex == start {
-			return -1 // 插入失败，或没找到
-		}
+	// 探测完还没找到，插入模式下复用遇到的删除标记
+	if reuse >= 0 {
+		return reuse, reuseDistance
 	}
+	// 说明表满了或冲突严重（理应在插入前扩容）
+	return -1, limit // 插入失败，或没找到
 }
 
 // Insert 插入或更新键值
+//
+// 无法比较的键（例如切片、map）不会导致 panic，但与任何键都不相等：
+// 每次插入都会占用新的槽位且无法再查找或删除。这类键需要通过 WithKeyEqual 提供比较函数，
+// InsertBatch 会对这类键返回 ErrNotComparable。
 func (st *Table) Insert(key any, value any) {
-	// 当 size 超过 loadFactor * capacity 时，需要扩容
-	if float64(st.size+1) > float64(st.capacity)*st.loadFactor {
-		st.resize(st.capacity * 2)
+	st.put(key, value, 0)
+	st.checkInvariants()
+}
+
+// TryInsert 与 Insert 相同，但在无法插入时返回错误而不是 panic 或静默接受：
+// 表已冻结时返回 ErrFrozen，键无法比较时返回 ErrNotComparable，返回错误时表不会被修改。
+// 槽位不足时总会扩容后重试，因此不会因为表满而失败
+func (st *Table) TryInsert(key any, value any) error {
+	if st.frozen {
+		return ErrFrozen
+	}
+	if err := st.checkKey(key); err != nil {
+		return err
+	}
+	st.put(key, value, 0)
+	return nil
+}
+
+// Swap 插入或更新键值，返回之前的值以及键之前是否存在
+func (st *Table) Swap(key any, value any) (previous any, loaded bool) {
+	return st.put(key, value, 0)
+}
+
+// put 插入或更新键值并设置过期时间，只探测一次，返回旧值以及键之前是否存在
+// 已过期但尚未清理的键视为不存在
+func (st *Table) put(key any, value any, expireAt int64) (previous any, loaded bool) {
+	st.checkWritable()
+
+	// 删除标记同样占据槽位、拉长探测链，因此和 size 一起与负载因子比较：
+	// 删除标记占多数时原容量重建即可，否则扩容
+	if float64(st.size+st.deleted+1) > float64(st.capacity)*st.loadFactor {
+		if st.deleted > st.size {
+			st.Compact()
+		} else {
+			st.resize(st.nextCapacity())
+		}
 	}
 
-	index := st.getIndex(key)
+	h := st.hash(key)
+	st.unshare()
 
 	// 找槽位，插入模式
-	slot := st.findSlot(index, key, true)
+	slot, distance := st.probeSlot(h, key, true)
+	if slot >= 0 && st.maxProbe > 0 && !st.chaining && distance > st.maxProbe && st.metas[slot]&0x03 != metaFull {
+		// 新键的探测距离超过上限，每次插入最多调整一次，避免恒定冲突时反复重建
+		// 拉链法的插入模式已经把空闲条目链入桶中，重试会再链入一个，因此不参与调整
+		st.relieveProbe()
+		h = st.hash(key)
+		slot = st.findSlot(h, key, true)
+	}
+	for slot < 0 {
+		// 布谷鸟哈希在当前容量下放不下新键，扩容后重试
+		st.resize(st.nextCapacity())
+		slot = st.findSlot(h, key, true)
+	}
+
+	meta := st.metas[slot] & 0x03
 
-	meta := st.entries[slot].meta & 0x03
+	// 多值模式下存入的是值列表
+	stored := value
 
 	// 如果当前槽位是空或删除，则是新插入
 	if meta == metaEmpty || meta == metaDel {
+		if st.multiValue {
+			stored = appendValue(nil, value)
+		}
+		if meta == metaDel {
+			st.deleted--
+		}
 		st.size++
-		st.entries[slot].meta = metaFull
+		st.metas[slot] = metaFull | tagOf(h)
 		st.entries[slot].key = key
-		st.entries[slot].value = value
-	} else {
-		// 如果是已占用，则说明 key 相同，更新值
-		st.entries[slot].value = value
+		st.entries[slot].value = st.wrapValue(stored)
+		st.setExpireAt(slot, expireAt)
+		if st.timestamps {
+			now := st.now().UnixNano()
+			st.side.createdAt[slot] = now
+			st.side.accessedAt[slot] = now
+		}
+		if st.lru != nil {
+			st.side.lru[slot] = st.lru.PushBack(key)
+		}
+		if st.order != nil {
+			st.side.order[slot] = st.order.PushBack(key)
+		}
+		if st.bloom != nil {
+			st.bloom.add(h)
+		}
+		if st.onInsert != nil {
+			st.onInsert(key, value)
+		}
+		st.evictOverflow()
+		return nil, false
+	}
+
+	// 如果是已占用，则说明 key 相同，更新值
+	previous, loaded = st.valueAt(slot), true
+	if !st.live(slot, st.expireNow()) {
+		previous, loaded = nil, false
+	}
+	if st.multiValue {
+		stored = appendValue(previous, value)
+	}
+	st.entries[slot].value = st.wrapValue(stored)
+	st.setExpireAt(slot, expireAt)
+	st.touch(slot)
+	if st.onInsert != nil {
+		st.onInsert(key, value)
+	}
+	return previous, loaded
+}
+
+// relieveProbe 在插入的探测距离超过 WithMaxProbe 的上限时调用
+// 使用默认哈希时换一个随机种子重建，冲突来自哈希值本身时扩容无济于事；
+// 每次调整容量之间最多换一次种子，普通键在高负载下也会偶尔出现长探测，换种子无济于事时改为扩容。
+// 使用自定义哈希时无法换种子，负载达到扩容阈值的一半后提前扩容，更低的负载下扩容只会浪费内存
+func (st *Table) relieveProbe() {
+	if st.hashFn == nil && st.typedHash == nil && st.reseedEpoch != st.resizeCount+1 {
+		st.seed = randomSeed()
+		st.Rehash(nil)
+		st.reseedEpoch = st.resizeCount + 1
+		return
+	}
+	if float64(st.size+1) > float64(st.capacity)*st.loadFactor/2 {
+		st.resize(st.nextCapacity())
+	}
+}
+
+// checkKey 检查键是否可以存入表中
+func (st *Table) checkKey(key any) error {
+	if st.keyEqual != nil || key == nil {
+		return nil
 	}
+	if !reflect.ValueOf(key).Comparable() {
+		return fmt.Errorf("%w: %T", ErrNotComparable, key)
+	}
+	return nil
 }
 
 // InsertBatch 批量插入键值，避免多次触发扩容
+// nil 切片与空切片等价；keys 与 values 长度不一致或存在无法比较的键时返回错误，
+// 同一批中重复的键按 WithDuplicateBatchPolicy 处理；返回错误时表不会被修改
 func (st *Table) InsertBatch(keys []any, values []any) error {
+	if st.frozen {
+		return ErrFrozen
+	}
 	if len(keys) != len(values) {
 		return fmt.Errorf("length not match")
 	}
+	// 先校验全部输入再修改，保证失败时不会只插入一部分
+	for _, k := range keys {
+		if err := st.checkKey(k); err != nil {
+			return err
+		}
+	}
+	// skip[i] 为 true 时忽略第 i 个键
+	skip, err := st.batchDuplicates(keys)
+	if err != nil {
+		return err
+	}
 
 	totalIncoming := len(keys)
 	// 先一次性检查并确保容量足够
 	// 可能一次扩容不足，循环直到足够
 	for float64(st.size+totalIncoming) > float64(st.capacity)*st.loadFactor {
-		st.resize(st.capacity * 2)
+		st.resize(st.nextCapacity())
 	}
 
 	// 再进行逐个插入
 	for i, k := range keys {
+		if skip != nil && skip[i] {
+			continue
+		}
 		st.Insert(k, values[i])
 	}
 	return nil
 }
 
-// Find 查找键对应的值，找不到返回 nil
-func (st *Table) Find(key any) any {
-	index := st.getIndex(key)
+// InsertBatchResult 与 InsertBatch 相同，但逐个报告错误：返回与 keys 等长的切片，第 i 个元素为第 i 个键的错误
+// 无法比较的键对应 ErrNotComparable，WithDuplicateBatchPolicy 为 DuplicateError 时重复的键对应 ErrDuplicateKey，
+// 表已冻结时全部为 ErrFrozen；出错的键被跳过，其余键照常插入。keys 与 values 长度不一致时 panic
+func (st *Table) InsertBatchResult(keys []any, values []any) []error {
+	if len(keys) != len(values) {
+		panic("table: keys and values length not match")
+	}
+
+	errs := make([]error, len(keys))
+	if st.frozen {
+		for i := range errs {
+			errs[i] = ErrFrozen
+		}
+		return errs
+	}
+
+	var seen *Table
+	if st.duplicatePolicy != DuplicateOverwrite {
+		seen = st.batchSeen(len(keys))
+	}
+	st.Grow(len(keys))
+	for i, k := range keys {
+		if errs[i] = st.checkKey(k); errs[i] != nil {
+			continue
+		}
+		if seen != nil && !seen.InsertIfAbsent(k, nil) {
+			if st.duplicatePolicy == DuplicateError {
+				errs[i] = fmt.Errorf("%w: %v", ErrDuplicateKey, k)
+			}
+			continue
+		}
+		st.Insert(k, values[i])
+	}
+	return errs
+}
+
+// PutAll 按顺序插入或更新键值对，按传入的数量预先扩容
+// 重复的键以最后一次出现的值为准，不受 WithDuplicateBatchPolicy 影响
+func (st *Table) PutAll(pairs []Pair) {
+	st.checkWritable()
+
+	st.Grow(len(pairs))
+	for _, p := range pairs {
+		st.Insert(p.Key, p.Value)
+	}
+}
+
+// batchDuplicates 按 WithDuplicateBatchPolicy 检查同一批中重复的键
+// DuplicateError 时遇到重复返回 ErrDuplicateKey，DuplicateKeepFirst 时返回需要忽略的位置，
+// DuplicateOverwrite 时不做检查；没有需要忽略的键时返回 nil
+func (st *Table) batchDuplicates(keys []any) ([]bool, error) {
+	if st.duplicatePolicy == DuplicateOverwrite {
+		return nil, nil
+	}
+
+	seen := st.batchSeen(len(keys))
+	var skip []bool
+	for i, k := range keys {
+		if seen.InsertIfAbsent(k, nil) {
+			continue
+		}
+		if st.duplicatePolicy == DuplicateError {
+			return nil, fmt.Errorf("%w: %v", ErrDuplicateKey, k)
+		}
+		if skip == nil {
+			skip = make([]bool, len(keys))
+		}
+		skip[i] = true
+	}
+	return skip, nil
+}
+
+// batchSeen 返回用于判断同一批中重复键的临时表，与当前表使用相同的哈希和比较方式
+func (st *Table) batchSeen(n int) *Table {
+	seen := NewTable(CapacityFor(n, 0.75))
+	seen.hashFn = st.hash
+	seen.keyEqual = st.keyEqual
+	return seen
+}
+
+// GetOrInsertBatch 批量执行 GetOrInsert，按传入的数量预先扩容
+// 键已存在时返回已有的值且 loaded 为 true，否则插入 values 中对应的值且 loaded 为 false；
+// keys 中重复的键以第一次插入的值为准，keys 与 values 长度不一致时 panic
+func (st *Table) GetOrInsertBatch(keys []any, values []any) (actuals []any, loaded []bool) {
+	if len(keys) != len(values) {
+		panic("table: keys and values length not match")
+	}
+	st.checkWritable()
+
+	for float64(st.size+len(keys)) > float64(st.capacity)*st.loadFactor {
+		st.resize(st.nextCapacity())
+	}
+
+	actuals = make([]any, len(keys))
+	loaded = make([]bool, len(keys))
+	for i, k := range keys {
+		var inserted bool
+		actuals[i], inserted = st.GetOrInsert(k, values[i])
+		loaded[i] = !inserted
+	}
+	return actuals, loaded
+}
 
-	slot := st.findSlot(index, key, false)
+// lookup 返回键所在的未过期槽位索引，找不到返回 -1
+// 找到已过期的键时顺便将其删除（冻结的表只视为不存在）
+func (st *Table) lookup(key any) int {
+	return st.liveSlot(st.lookupSlot(key))
+}
+
+// liveSlot 检查 lookupSlot 找到的槽位是否过期，过期时顺便将其删除并返回 -1
+func (st *Table) liveSlot(slot int) int {
+	if slot < 0 || !st.hasTTL && !st.weakValues || st.live(slot, st.expireNow()) {
+		return slot
+	}
+
+	if !st.frozen {
+		st.removeAt(slot)
+	}
+	return -1
+}
+
+// lookupSlot 返回键所在的已占用槽位索引，不检查是否过期，找不到返回 -1
+func (st *Table) lookupSlot(key any) int {
+	// 空表不需要计算哈希和探测
+	if st.size == 0 {
+		return -1
+	}
+
+	h := st.hash(key)
+	// 布隆过滤器判断不存在的键一定不存在，不需要探测
+	if st.bloom != nil && !st.bloom.mayContain(h) {
+		return -1
+	}
+
+	slot := st.findSlot(h, key, false)
 	if slot < 0 {
-		return nil
+		return -1
 	}
 
-	meta := st.entries[slot].meta & 0x03
+	meta := st.metas[slot] & 0x03
 	// 如果是空槽位或删除槽位，说明找不到对应键
 	if meta == metaEmpty || meta == metaDel {
-		return nil
+		return -1
+	}
+
+	return slot
+}
+
+// Find 查找键对应的值，找不到返回 nil
+func (st *Table) Find(key any) any {
+	value, _ := st.FindOK(key)
+	return value
+}
+
+// FindOK 查找 key，第二个返回值表示键是否存在，可以区分存入的 nil 与不存在
+// 设置了 WithLoader 时，键不存在会调用加载函数
+func (st *Table) FindOK(key any) (any, bool) {
+	if value, ok := st.findOK(key); ok {
+		return value, true
+	}
+	return st.load(key)
+}
+
+// findOK 与 FindOK 相同，但不调用加载函数
+func (st *Table) findOK(key any) (any, bool) {
+	value, ok := st.get(key)
+	st.recordLookup(ok)
+	return value, ok
+}
+
+// get 查找键并刷新访问记录，不调用加载函数，也不计入命中统计
+func (st *Table) get(key any) (any, bool) {
+	slot := st.lookup(key)
+	if slot < 0 {
+		return nil, false
 	}
+	st.touch(slot)
 
-	return st.entries[slot].value
+	return st.valueAt(slot), true
 }
 
-// FindBatch 批量查找键
+// Contains 判断键是否存在
+func (st *Table) Contains(key any) bool {
+	return st.lookup(key) >= 0
+}
+
+// FindOr 查找键对应的值，键不存在时返回 fallback
+// 是否返回 fallback 取决于键是否存在，存储的值为 nil 时依然返回 nil
+func (st *Table) FindOr(key any, fallback any) any {
+	if value, ok := st.FindOK(key); ok {
+		return value
+	}
+	return fallback
+}
+
+// FindPath 把表视为嵌套结构逐级查找：FindPath("a", "b") 先查找 "a"，其值须为 *Table，再在其中查找 "b"
+// 任意一级的键不存在或中间一级的值不是 *Table 时返回 false；没有传入键时返回表本身
+func (st *Table) FindPath(keys ...any) (any, bool) {
+	var current any = st
+	for _, key := range keys {
+		level, ok := current.(*Table)
+		if !ok || level == nil {
+			return nil, false
+		}
+		if current, ok = level.FindOK(key); !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// GetOrInsert 键存在时返回已有的值，否则插入 value 并返回，第二个返回值表示是否新插入
+func (st *Table) GetOrInsert(key any, value any) (actual any, inserted bool) {
+	return st.GetOrCompute(key, func() any { return value })
+}
+
+// GetOrCompute 与 GetOrInsert 相同，但只在键不存在时才调用 factory 生成要插入的值
+func (st *Table) GetOrCompute(key any, factory func() any) (actual any, computed bool) {
+	if value, ok := st.get(key); ok {
+		return value, false
+	}
+
+	value := factory()
+	st.put(key, value, 0)
+	return value, true
+}
+
+// InsertIfAbsent 键不存在时插入，返回是否插入；键已存在时不做任何修改
+func (st *Table) InsertIfAbsent(key any, value any) bool {
+	_, inserted := st.GetOrInsert(key, value)
+	return inserted
+}
+
+// FindBatch 批量查找键，keys 为 nil 或空切片时返回长度为 0 的结果
 func (st *Table) FindBatch(keys []any) []any {
 	results := make([]any, len(keys))
+	// 设置了加载函数时空表仍需逐个查找，由加载函数补全
+	if st.size == 0 && st.loader == nil {
+		return results
+	}
 	for i, key := range keys {
 		results[i] = st.Find(key)
 	}
 	return results
 }
 
+// ContainsBatch 批量判断键是否存在，结果与 keys 一一对应，不读取值
+func (st *Table) ContainsBatch(keys []any) []bool {
+	results := make([]bool, len(keys))
+	if st.size == 0 {
+		return results
+	}
+	for i, key := range keys {
+		results[i] = st.lookup(key) >= 0
+	}
+	return results
+}
+
+// FindBatchOK 批量查找键，返回对应的值与是否存在
+func (st *Table) FindBatchOK(keys []any) ([]any, []bool) {
+	results := make([]any, len(keys))
+	found := make([]bool, len(keys))
+	if st.size == 0 && st.loader == nil {
+		return results, found
+	}
+	for i, key := range keys {
+		results[i], found[i] = st.FindOK(key)
+	}
+	return results, found
+}
+
 // Delete 删除 key，成功返回 true，失败返回 false
 func (st *Table) Delete(key any) bool {
-	index := st.getIndex(key)
+	st.checkWritable()
 
-	slot := st.findSlot(index, key, false)
+	slot := st.lookup(key)
 	if slot < 0 {
 		return false
 	}
 
-	meta := st.entries[slot].meta & 0x03
-	if meta == metaFull && st.entries[slot].key == key {
+	st.removeAt(slot)
+	st.checkInvariants()
+	return true
+}
+
+// defaultCompactHint 未设置 WithCompactThreshold 时 DeleteWithHint 建议整理的墓碑比例
+const defaultCompactHint = 0.25
+
+// DeleteWithHint 与 Delete 相同，同时返回删除后墓碑数是否超过了容量的一定比例，建议调用 Compact
+// 比例使用 WithCompactThreshold 设置的阈值（此时超过阈值会自动整理，通常返回 false），默认为 0.25；
+// 是否整理由调用方决定，本方法不会整理
+func (st *Table) DeleteWithHint(key any) (deleted bool, shouldCompact bool) {
+	if !st.Delete(key) {
+		return false, false
+	}
+
+	ratio := st.compactThreshold
+	if ratio == 0 {
+		ratio = defaultCompactHint
+	}
+	return true, float64(st.deleted) > float64(st.capacity)*ratio
+}
+
+// removeAt 删除指定的已占用槽位，删除后按需自动缩容
+func (st *Table) removeAt(slot int) {
+	st.clearSlot(slot)
+	st.maybeAutoShrink()
+	st.maybeAutoCompact()
+}
+
+// clearSlot 删除指定的已占用槽位，不会调整容量，可以在遍历槽位时安全调用
+func (st *Table) clearSlot(slot int) {
+	st.checkWritable()
+
+	key, value := st.entries[slot].key, st.valueAt(slot)
+	st.unshare()
+
+	if st.lru != nil {
+		st.lru.Remove(st.side.lru[slot])
+	}
+	if st.order != nil {
+		st.order.Remove(st.side.order[slot])
+	}
+
+	if st.chaining {
+		// 拉链法直接把条目放回空闲链表，不需要删除标记
+		st.unlinkChain(slot)
+		st.metas[slot] = metaEmpty
+	} else if st.cuckoo {
+		// 布谷鸟哈希查找时不探测，不需要删除标记
+		if st.inStash(slot) {
+			st.stashed--
+		}
+		st.metas[slot] = metaEmpty
+	} else {
 		// 逻辑删除，只标记为删除
-		st.entries[slot].meta = metaDel
-		st.entries[slot].key = nil
-		st.entries[slot].value = nil
-		st.size--
-		return true
+		st.metas[slot] = metaDel
+		st.deleted++
+	}
+	st.entries[slot] = Entry{}
+	st.side.clearSlot(slot)
+	st.size--
+
+	if st.onDelete != nil {
+		st.onDelete(key, value)
 	}
-	return false
 }
 
-// resize 扩容哈希表
-func (st *Table) resize(newCapacity int) {
-	newTable := NewTable(newCapacity)
+// maybeAutoShrink 开启自动缩容且 size 低于阈值时缩容
+// 缩容时保留余量：目标容量使 size 恰好位于缩容阈值与负载因子的中点，而不是 Shrink 的最小容量，
+// 之后插入或删除与容量成正比的数量才会再次扩容或缩容，交替插入删除不会反复重建
+func (st *Table) maybeAutoShrink() {
+	if st.autoShrink <= 0 || st.capacity <= st.minCapacity || float64(st.size) >= float64(st.capacity)*st.autoShrink {
+		return
+	}
+
+	target := int(math.Ceil(float64(st.size) / ((st.autoShrink + st.loadFactor) / 2)))
+	if target < st.capacity {
+		st.resize(target)
+	}
+}
+
+// maybeAutoCompact 开启自动整理且墓碑比例超过阈值时整理
+func (st *Table) maybeAutoCompact() {
+	if st.compactThreshold > 0 && float64(st.deleted) > float64(st.capacity)*st.compactThreshold {
+		st.Compact()
+	}
+}
+
+// Increment 把键对应的整数值加上 delta 并返回新值，键不存在时视为 0
+// 已存在的值可以是任意有符号或无符号整数类型，累加后统一以 int64 存储；
+// 已存在的键保留原来的过期时间；值不是整数时返回 ErrNotInteger，且不修改表
+func (st *Table) Increment(key any, delta int64) (int64, error) {
+	if st.frozen {
+		return 0, ErrFrozen
+	}
+
+	// 更新已存在的键时与 Upsert 相同，保留原来的过期时间
+	var current, expireAt int64
+	if slot := st.lookup(key); slot >= 0 {
+		expireAt = st.expireAtSlot(slot)
+		v := reflect.ValueOf(st.valueAt(slot))
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			current = v.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			current = int64(v.Uint())
+		default:
+			return 0, fmt.Errorf("%w: %T", ErrNotInteger, st.valueAt(slot))
+		}
+	}
+
+	current += delta
+	st.put(key, current, expireAt)
+	return current, nil
+}
+
+// Pop 删除并返回任意一个键值对，表为空时 ok 为 false
+// 从上次停下的位置继续扫描，连续调用直到表为空的总开销为 O(capacity)
+func (st *Table) Pop() (key, value any, ok bool) {
+	st.checkWritable()
+
+	if st.size == 0 {
+		return nil, nil, false
+	}
+
+	now := st.expireNow()
 	for i := 0; i < st.capacity; i++ {
-		meta := st.entries[i].meta & 0x03
-		if meta == metaFull {
-			key := st.entries[i].key
-			value := st.entries[i].value
-			newTable.Insert(key, value)
+		slot := (st.popCursor + i) % st.capacity
+		if !st.live(slot, now) {
+			continue
+		}
+
+		key, value = st.entries[slot].key, st.valueAt(slot)
+		st.popCursor = (slot + 1) % st.capacity
+		st.removeAt(slot)
+		return key, value, true
+	}
+	return nil, nil, false
+}
+
+// ReplaceIfPresent 键存在时把值替换为 value，返回是否发生了替换；键不存在时不会插入
+// 替换只修改值，不改变键的过期时间
+func (st *Table) ReplaceIfPresent(key any, value any) bool {
+	st.checkWritable()
+
+	slot := st.lookup(key)
+	if slot < 0 {
+		return false
+	}
+
+	st.unshare()
+	st.entries[slot].value = st.wrapValue(value)
+	st.touch(slot)
+	if st.onInsert != nil {
+		st.onInsert(key, value)
+	}
+	return true
+}
+
+// Upsert 键不存在时插入 value，存在时把值替换为 combine(existing, value)，返回最终存储的值
+// 可用于累加、合并等操作，combine 中不能修改表
+func (st *Table) Upsert(key, value any, combine func(existing, incoming any) any) any {
+	st.checkWritable()
+
+	slot := st.lookup(key)
+	if slot < 0 {
+		st.put(key, value, 0)
+		return value
+	}
+
+	merged := combine(st.valueAt(slot), value)
+	st.unshare()
+	st.entries[slot].value = st.wrapValue(merged)
+	st.touch(slot)
+	if st.onInsert != nil {
+		st.onInsert(key, merged)
+	}
+	return merged
+}
+
+// CompareAndSwap 当键存在且当前值与 old 相等时，把值替换为 new
+// eq 为 nil 时使用 == 比较，无法比较的值（例如切片、map）视为不相等，返回是否发生了替换
+func (st *Table) CompareAndSwap(key, old, new any, eq func(a, b any) bool) bool {
+	st.checkWritable()
+
+	slot := st.lookup(key)
+	if slot < 0 {
+		return false
+	}
+	if eq == nil {
+		eq = valuesEqual
+	}
+	if !eq(st.valueAt(slot), old) {
+		return false
+	}
+
+	st.unshare()
+	st.entries[slot].value = st.wrapValue(new)
+	if st.onInsert != nil {
+		st.onInsert(key, new)
+	}
+	return true
+}
+
+// CompareAndDelete 当键存在且当前值与 old 相等时，删除该键
+// eq 为 nil 时与 CompareAndSwap 相同，使用 == 比较，返回是否发生了删除
+func (st *Table) CompareAndDelete(key, old any, eq func(a, b any) bool) bool {
+	st.checkWritable()
+
+	slot := st.lookup(key)
+	if slot < 0 {
+		return false
+	}
+	if eq == nil {
+		eq = valuesEqual
+	}
+	if !eq(st.valueAt(slot), old) {
+		return false
+	}
+
+	st.removeAt(slot)
+	return true
+}
+
+// resize 调整哈希表容量，保留负载因子、哈希函数等配置
+func (st *Table) resize(newCapacity int) {
+	st.resizeTo(newCapacity, st.inPlace)
+}
+
+// resizeTo 与 resize 相同，inPlace 为 false 时总是分配新数组，不沿用原来的底层数组
+func (st *Table) resizeTo(newCapacity int, inPlace bool) {
+	st.checkWritable()
+
+	newCapacity = st.normalizeCapacity(newCapacity)
+	if newCapacity > maxCapacity {
+		panic(fmt.Errorf("%w: %d", ErrCapacityOverflow, newCapacity))
+	}
+	// 不超过 maxCapacity 的容量仍可能超出运行时单次分配的上限，此时分配数组会 panic，
+	// 恢复调用前的状态后同样以 ErrCapacityOverflow panic
+	saved := *st
+	defer func() {
+		if r := recover(); r != nil {
+			if !isAllocOverflow(r) {
+				panic(r)
+			}
+			*st = saved
+			panic(fmt.Errorf("%w: %d", ErrCapacityOverflow, newCapacity))
 		}
+	}()
+
+	oldCapacity := st.capacity
+	if inPlace && !st.chaining && !st.cuckoo {
+		st.unshare()
+		st.rehashInPlace(newCapacity)
+	} else {
+		st.reinsertAll(newCapacity)
+	}
+
+	st.rebuildBloom()
+	st.popCursor = 0
+	st.resizeCount++
+	if st.onResize != nil {
+		st.onResize(oldCapacity, st.capacity)
 	}
-	// 用新的 table 替换旧 table
-	*st = *newTable
+	if st.capacity >= oldCapacity {
+		st.emitResize(oldCapacity, st.capacity, ResizeGrow)
+	} else {
+		st.emitResize(oldCapacity, st.capacity, ResizeShrink)
+	}
+	st.checkInvariants()
+}
+
+// isAllocOverflow 判断 recover 得到的值是否为分配的长度超出运行时上限引起的 panic
+func isAllocOverflow(r any) bool {
+	err, ok := r.(runtime.Error)
+	return ok && strings.HasSuffix(err.Error(), "len out of range")
 }
 
-// Expand 扩容哈希表到指定的新容量
+// reinsertAll 分配容量为 newCapacity 的新数组，把所有键值对重新插入，原数组保持不变
+// 布谷鸟哈希在该容量下放不下所有键时换用更大的容量重试
+func (st *Table) reinsertAll(newCapacity int) {
+	old, oldMetas, oldSide, wasShared := st.entries, st.metas, st.side, st.shared
+	for !st.placeAll(old, oldMetas, &oldSide, newCapacity) {
+		newCapacity = st.normalizeCapacity(2 * newCapacity)
+	}
+	if wasShared {
+		st.cloneOrder()
+	}
+}
+
+// placeAll 把 old 中的键值对插入容量为 capacity 的新数组，有键找不到槽位时返回 false
+func (st *Table) placeAll(old []Entry, oldMetas []byte, oldSide *sideArrays, capacity int) bool {
+	st.entries = make([]Entry, capacity)
+	st.metas = make([]byte, capacity)
+	st.side = st.allocSide(capacity)
+	st.shared = false
+	st.capacity = capacity
+	st.size = 0
+	st.deleted = 0
+	st.stashed = 0
+	if st.chaining {
+		st.initChains()
+	}
+	for i := range old {
+		if oldMetas[i]&0x03 == metaFull {
+			// Rehash 时哈希函数已经改变，标签需要重新计算
+			h := st.hash(old[i].key)
+			slot := st.findSlot(h, old[i].key, true)
+			if slot < 0 {
+				return false
+			}
+			st.entries[slot] = old[i]
+			st.side.copySlot(slot, oldSide, i)
+			st.metas[slot] = metaFull | tagOf(h)
+			st.size++
+		}
+	}
+	return true
+}
+
+// Compact 在容量不变的情况下重建表，清除所有删除标记，缩短被删除标记拉长的探测链
+// 插入时删除标记占多数会自动调用，不计入 ResizeCount，也不触发 OnResize 回调
+func (st *Table) Compact() {
+	st.checkWritable()
+	if st.deleted == 0 {
+		return
+	}
+
+	oldCapacity := st.capacity
+	if st.inPlace && !st.chaining && !st.cuckoo {
+		st.unshare()
+		st.rehashInPlace(st.capacity)
+	} else {
+		st.reinsertAll(st.capacity)
+	}
+	st.rebuildBloom()
+	st.popCursor = 0
+	st.emitResize(oldCapacity, st.capacity, ResizeCompact)
+}
+
+// Expand 扩容哈希表到指定的新容量，超过容量上限时以 ErrCapacityOverflow panic
 func (st *Table) Expand(newCapacity int) {
 	if newCapacity <= st.capacity {
 		return
@@ -217,17 +1201,119 @@ func (st *Table) Expand(newCapacity int) {
 	st.resize(newCapacity)
 }
 
-// Shrink 缩小哈希表
-func (st *Table) Shrink() {
-	const minCap = 8
+// TryExpand 与 Expand 相同，但 newCapacity 超过容量上限或超出运行时单次分配的上限时
+// 返回 ErrCapacityOverflow 而不是 panic，表已冻结时返回 ErrFrozen，返回错误时表不会被修改
+func (st *Table) TryExpand(newCapacity int) (err error) {
+	if st.frozen {
+		return ErrFrozen
+	}
+	if newCapacity > maxCapacity {
+		return fmt.Errorf("%w: %d", ErrCapacityOverflow, newCapacity)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok && errors.Is(e, ErrCapacityOverflow) {
+				err = e
+				return
+			}
+			panic(r)
+		}
+	}()
+	st.Expand(newCapacity)
+	return nil
+}
+
+// Reserve 预先扩容，使表在当前负载因子下能容纳 n 个键值对而不再触发扩容
+func (st *Table) Reserve(n int) {
+	if float64(n) <= float64(st.capacity)*st.loadFactor {
+		return
+	}
+
+	st.resize(CapacityFor(n, st.loadFactor))
+}
+
+// Grow 预先扩容，保证在当前键值对之外再插入 additional 个新键时不会触发扩容
+// 与 Reserve 不同，参数是相对当前 size 的增量；墓碑也会占用负载，必要时一并清除
+// additional 为负数时 panic
+func (st *Table) Grow(additional int) {
+	if additional < 0 {
+		panic("table: negative grow")
+	}
+
+	need := st.size + additional
+	if float64(need+st.deleted) <= float64(st.capacity)*st.loadFactor {
+		return
+	}
+
+	st.resize(max(CapacityFor(need, st.loadFactor), st.capacity))
+}
+
+// CapacityFor 返回在负载因子 loadFactor 下容纳 n 个键值对而不触发扩容所需的最小容量
+// 表的容量不要求是 2 的幂，因此结果不会向上取整到 2 的幂，但不会小于最小容量 8
+// loadFactor 必须在 (0, 1) 之间
+func CapacityFor(n int, loadFactor float64) int {
+	if loadFactor <= 0 || loadFactor >= 1 {
+		panic("table: load factor must be in (0, 1)")
+	}
+
+	capacity := int(math.Ceil(float64(n) / loadFactor))
+	// 避免浮点误差导致容量差一个
+	for float64(n) > float64(capacity)*loadFactor {
+		capacity++
+	}
+	if capacity < 8 {
+		capacity = 8
+	}
+	return capacity
+}
+
+// newLike 创建一个与当前表配置相同的空表，不复制回调
+func (st *Table) newLike(capacity int) *Table {
+	return NewTable(capacity, func(res *Table) {
+		res.loadFactor = st.loadFactor
+		res.growthFactor = st.growthFactor
+		res.autoShrink = st.autoShrink
+		res.compactThreshold = st.compactThreshold
+		res.hashFn = st.hashFn
+		res.probe = st.probe
+		res.keyEqual = st.keyEqual
+		res.minCapacity = st.minCapacity
+		res.inPlace = st.inPlace
+		res.chaining = st.chaining
+		res.cuckoo = st.cuckoo
+		res.weakValues = st.weakValues
+		res.multiValue = st.multiValue
+		res.timestamps = st.timestamps
+		res.typedHash = st.typedHash
+		res.duplicatePolicy = st.duplicatePolicy
+		res.maxProbe = st.maxProbe
+		res.primeCapacity = st.primeCapacity
+		res.clock = st.clock
+		res.maxEntries = st.maxEntries
+		if st.lru != nil {
+			res.lru = list.New()
+		}
+		if st.order != nil {
+			res.order = list.New()
+		}
+		if st.bloom != nil {
+			res.bloomN, res.bloomFP = st.bloomN, st.bloomFP
+			res.bloom = newBloomFilter(st.bloomN, st.bloomFP)
+		}
+	})
+}
 
-	if st.capacity <= minCap {
+// Shrink 按当前负载因子把哈希表缩小到刚好容纳现有的键值对，不会低于最小容量
+// 缩容保留负载因子、哈希函数、增长倍数等所有配置
+func (st *Table) Shrink() {
+	if st.capacity <= st.minCapacity {
 		return
 	}
 
 	idealCap := int(math.Ceil(float64(st.size) / st.loadFactor))
-	if idealCap < minCap {
-		idealCap = minCap
+	if idealCap < st.minCapacity {
+		idealCap = st.minCapacity
 	}
 
 	if idealCap < st.capacity {
@@ -235,12 +1321,213 @@ func (st *Table) Shrink() {
 	}
 }
 
+// ShrinkToFit 把容量缩小到当前负载因子下恰好容纳 size 个键值对的最小容量，同时清除删除标记
+// 与 Shrink 不同，容量按浮点误差修正后的精确值计算，只受最小容量限制（不受 CapacityFor 的下限 8 限制），
+// 用于大量删除后尽可能回收内存；表的容量不要求是 2 的幂，因此不会向上取整
+func (st *Table) ShrinkToFit() {
+	target := st.fitCapacity()
+	if target < st.capacity || st.deleted > 0 {
+		st.resize(target)
+	}
+}
+
+// fitCapacity 返回当前负载因子下恰好容纳 size 个键值对的最小容量
+func (st *Table) fitCapacity() int {
+	target := int(math.Ceil(float64(st.size) / st.loadFactor))
+	for float64(st.size) > float64(target)*st.loadFactor {
+		target++
+	}
+	return st.normalizeCapacity(target)
+}
+
+// Clear 删除所有键值对，保留容量和底层数组，之后重新插入不需要再扩容
+// 每个被删除的键值对都会触发 OnDelete 回调；需要把内存还给运行时时在之后调用 Trim
+func (st *Table) Clear() {
+	st.checkWritable()
+
+	var removed []Entry
+	if st.onDelete != nil {
+		removed = make([]Entry, 0, st.size)
+		st.forEach(func(slot int) bool {
+			removed = append(removed, Entry{key: st.entries[slot].key, value: st.valueAt(slot)})
+			return true
+		})
+	}
+
+	if st.shared {
+		// 底层数组与快照共享，不能原地清空
+		st.entries = make([]Entry, st.capacity)
+		st.metas = make([]byte, st.capacity)
+		st.side = st.allocSide(st.capacity)
+		st.shared = false
+	} else {
+		clear(st.entries)
+		clear(st.metas)
+		st.side.clearRange(0, len(st.entries))
+	}
+	st.size = 0
+	st.deleted = 0
+	st.stashed = 0
+	if st.chaining {
+		st.initChains()
+	}
+	if st.lru != nil {
+		st.lru = list.New()
+	}
+	if st.order != nil {
+		st.order = list.New()
+	}
+	st.rebuildBloom()
+	st.popCursor = 0
+
+	for _, e := range removed {
+		st.onDelete(e.key, e.value)
+	}
+}
+
+// Trim 把容量缩小到恰好容纳当前键值对的最小容量，并且总是分配新的底层数组，使原来的大数组可以被回收
+// 适合在 Clear 或大量删除之后调用；与 ShrinkToFit 不同，开启 WithInPlaceResize 时同样重新分配
+func (st *Table) Trim() {
+	target := st.fitCapacity()
+	if target == st.capacity && cap(st.entries) == st.capacity && st.deleted == 0 {
+		return
+	}
+	st.resizeTo(target, false)
+}
+
 // Size 返回当前存储键值对的数量
 func (st *Table) Size() int {
 	return st.size
 }
 
+// DeletedCount 返回当前墓碑的数量，墓碑占用槽位并拉长探测链，可以调用 Compact 清除
+func (st *Table) DeletedCount() int {
+	return st.deleted
+}
+
+// ResizeCount 返回表累计调整容量的次数
+func (st *Table) ResizeCount() int {
+	return st.resizeCount
+}
+
+// OnResize 设置调整容量后的回调，fn 为 nil 时取消回调
+func (st *Table) OnResize(fn func(oldCap, newCap int)) {
+	st.onResize = fn
+}
+
+// OnInsert 设置插入或更新成功后的回调，更新已存在的键时传入新值，fn 为 nil 时取消回调
+// 调整容量时的内部重新插入不会触发回调
+func (st *Table) OnInsert(fn func(key, value any)) {
+	st.onInsert = fn
+}
+
+// OnDelete 设置删除成功后的回调，传入被删除的键和值，fn 为 nil 时取消回调
+func (st *Table) OnDelete(fn func(key, value any)) {
+	st.onDelete = fn
+}
+
+// Len 返回当前存储键值对的数量，与 Size 相同
+func (st *Table) Len() int {
+	return st.size
+}
+
+// IsEmpty 判断表中是否没有键值对，删除标记不计入
+func (st *Table) IsEmpty() bool {
+	return st.size == 0
+}
+
 // Capacity 返回当前哈希表容量
 func (st *Table) Capacity() int {
 	return st.capacity
 }
+
+// MemoryBytes 估算表本身占用的内存字节数，用于容量规划
+// 包括底层数组（按实际分配的长度计算）、表结构、布隆过滤器以及链表节点，
+// 不包括键和值指向的数据
+func (st *Table) MemoryBytes() int {
+	n := int(unsafe.Sizeof(*st)) + cap(st.entries)*int(unsafe.Sizeof(Entry{})) + cap(st.metas) + cap(st.heads)*int(unsafe.Sizeof(0)) + st.side.bytes()
+	if st.bloom != nil {
+		n += int(unsafe.Sizeof(*st.bloom)) + cap(st.bloom.bits)*8
+	}
+	if st.lru != nil {
+		n += st.lru.Len() * int(unsafe.Sizeof(list.Element{}))
+	}
+	if st.order != nil {
+		n += st.order.Len() * int(unsafe.Sizeof(list.Element{}))
+	}
+	return n
+}
+
+// Keys 返回所有键，开启 WithInsertionOrder 时按插入顺序，否则顺序不固定
+func (st *Table) Keys() []any {
+	keys := make([]any, 0, st.size)
+	st.forEach(func(slot int) bool {
+		keys = append(keys, st.entries[slot].key)
+		return true
+	})
+	return keys
+}
+
+// SortedKeys 返回按 less 从小到大排序的所有键，每次调用时排序，复杂度为 O(n log n)
+func (st *Table) SortedKeys(less func(a, b any) bool) []any {
+	keys := st.Keys()
+	sort.Slice(keys, func(i, j int) bool {
+		return less(keys[i], keys[j])
+	})
+	return keys
+}
+
+// Values 返回所有值，顺序与 Keys 一致
+func (st *Table) Values() []any {
+	values := make([]any, 0, st.size)
+	st.forEach(func(slot int) bool {
+		values = append(values, st.valueAt(slot))
+		return true
+	})
+	return values
+}
+
+// ToMap 把所有键值对转换为内置 map
+// 无法作为 map 键的键（例如切片，或包含切片的结构体，这类键需要配合 WithKeyEqual 使用）会被跳过
+func (st *Table) ToMap() map[any]any {
+	m := make(map[any]any, st.size)
+	st.forEach(func(slot int) bool {
+		key := st.entries[slot].key
+		if key == nil || reflect.ValueOf(key).Comparable() {
+			m[key] = st.valueAt(slot)
+		}
+		return true
+	})
+	return m
+}
+
+// Range 依次对每个键值对调用 fn，fn 返回 false 时停止
+// 开启 WithInsertionOrder 时按插入顺序，否则顺序不固定；fn 中不能修改表
+func (st *Table) Range(fn func(key, value any) bool) {
+	st.forEach(func(slot int) bool {
+		return fn(st.entries[slot].key, st.valueAt(slot))
+	})
+}
+
+// LoadFactor 返回当前的负载因子
+func (st *Table) LoadFactor() float64 {
+	return st.loadFactor
+}
+
+// SetLoadFactor 设置负载因子，f 必须在 (0, 1) 之间，开启 WithAutoShrink 时还必须大于缩容阈值
+// 当前 size 超出新负载因子允许的数量时会立即扩容
+func (st *Table) SetLoadFactor(f float64) error {
+	if st.frozen {
+		return ErrFrozen
+	}
+	if f <= 0 || f >= 1 {
+		return fmt.Errorf("load factor must be in (0, 1), got %v", f)
+	}
+	if f <= st.autoShrink {
+		return fmt.Errorf("load factor must be greater than auto shrink threshold %v, got %v", st.autoShrink, f)
+	}
+
+	st.loadFactor = f
+	st.Reserve(st.size)
+	return nil
+}
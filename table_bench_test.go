@@ -100,3 +100,208 @@ func BenchmarkExpand(b *testing.B) {
 		table.Expand(cap * 2)
 	}
 }
+
+func BenchmarkResizeCycle(b *testing.B) {
+	run := func(b *testing.B, opts ...Option) {
+		table := NewTable(1<<16, opts...)
+		for i := 0; i < 1000; i++ {
+			table.Insert(i, i)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			table.Shrink()
+			table.Expand(1 << 16)
+		}
+	}
+
+	b.Run("default", func(b *testing.B) { run(b) })
+	b.Run("in place", func(b *testing.B) { run(b, WithInPlaceResize()) })
+}
+
+// BenchmarkGrowOnly 只扩容、不先缩容，底层数组没有富余，原地重排同样需要分配新数组
+func BenchmarkGrowOnly(b *testing.B) {
+	run := func(b *testing.B, opts ...Option) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			table := NewTable(8, opts...)
+			for j := 0; j < 20000; j++ {
+				table.Insert(j, j)
+			}
+			b.StartTimer()
+
+			table.Expand(1 << 17)
+		}
+	}
+
+	b.Run("default", func(b *testing.B) { run(b) })
+	b.Run("in place", func(b *testing.B) { run(b, WithInPlaceResize()) })
+}
+
+func BenchmarkFindEmpty(b *testing.B) {
+	table := NewTable(16)
+	keys := make([]any, 100)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		table.Find(keys[i%len(keys)])
+	}
+}
+
+func BenchmarkFindMiss(b *testing.B) {
+	run := func(b *testing.B, opts ...Option) {
+		table := NewTable(1<<10, opts...)
+		// 使用开销很小的哈希函数，不命中时的耗时主要在于探测
+		table.hashFn = func(key any) uint64 { return mix64(uint64(key.(int))) }
+		for i := 0; i < 750; i++ {
+			table.Insert(i, i)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			table.Find(1000 + i%(1<<20))
+		}
+	}
+
+	b.Run("default", func(b *testing.B) { run(b) })
+	b.Run("bloom", func(b *testing.B) { run(b, WithBloomFilter(1000, 0.01)) })
+}
+
+func BenchmarkHighLoad(b *testing.B) {
+	run := func(b *testing.B, opts ...Option) {
+		table := NewTable(1<<16, opts...)
+		table.hashFn = func(key any) uint64 { return mix64(uint64(key.(int))) }
+		if err := table.SetLoadFactor(0.95); err != nil {
+			b.Fatal(err)
+		}
+		n := int(float64(table.Capacity()) * 0.94)
+		for i := 0; i < n; i++ {
+			table.Insert(i, i)
+		}
+
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			table.Find(i % (2 * n))
+		}
+	}
+
+	b.Run("open addressing", func(b *testing.B) { run(b) })
+	b.Run("chaining", func(b *testing.B) { run(b, WithChaining()) })
+}
+
+func BenchmarkFindLarge(b *testing.B) {
+	const n = 1 << 20
+	table := NewTable(n)
+	table.hashFn = func(key any) uint64 { return mix64(uint64(key.(int))) }
+	keys := make([]any, 2*n)
+	for i := range keys {
+		keys[i] = i
+	}
+	for i := 0; i < n/2; i++ {
+		table.Insert(keys[i], i)
+	}
+
+	b.Run("hit", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			table.Find(keys[(i*7919)%(n/2)])
+		}
+	})
+	b.Run("miss", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			table.Find(keys[n+(i*7919)%n])
+		}
+	})
+}
+
+func BenchmarkFindWorstCase(b *testing.B) {
+	run := func(b *testing.B, opts ...Option) {
+		table := NewTable(1<<12, opts...)
+		table.hashFn = func(key any) uint64 { return mix64(uint64(key.(int))) }
+		n := int(float64(table.Capacity()) * table.LoadFactor())
+		keys := make([]any, n)
+		for i := range keys {
+			keys[i] = i
+			table.Insert(i, i)
+		}
+
+		// 最长的查找需要检查的槽位数，反映查找延迟的波动
+		worst := 0
+		for _, k := range keys {
+			if d, _ := table.ProbeDistance(k); d+1 > worst {
+				worst = d + 1
+			}
+		}
+
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			table.Find(keys[i%n])
+		}
+		b.ReportMetric(float64(worst), "max-slots")
+	}
+
+	b.Run("linear", func(b *testing.B) { run(b) })
+	b.Run("cuckoo", func(b *testing.B) { run(b, WithCuckoo()) })
+}
+
+// BenchmarkIntKeys 比较整数键走快速路径与格式化为字符串后哈希的性能
+func BenchmarkIntKeys(b *testing.B) {
+	const n = 100000
+
+	run := func(b *testing.B, table *Table) {
+		for i := 0; i < n; i++ {
+			table.Insert(i, i)
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			table.Find(i % n)
+		}
+	}
+
+	b.Run("numeric", func(b *testing.B) {
+		run(b, NewTable(n))
+	})
+
+	b.Run("string", func(b *testing.B) {
+		table := NewTable(n)
+		table.hashFn = table.formatHash
+		run(b, table)
+	})
+}
+
+// BenchmarkFindString 比较 FindString 与 Find 查找字符串键的开销, 使用 -benchmem 查看分配次数
+func BenchmarkFindString(b *testing.B) {
+	const n = 10000
+	table := NewTable(n)
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+		table.InsertString(keys[i], i)
+	}
+
+	b.Run("FindString", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			table.FindString(keys[i%n])
+		}
+	})
+
+	b.Run("Find", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			table.Find(keys[i%n])
+		}
+	})
+}
@@ -1,15 +1,20 @@
 package table
 
 import (
+	"flag"
 	"fmt"
 	"math/rand/v2"
 	"testing"
 	"testing/quick"
 )
 
+// quickCount 设置 TestQuickCheckOperations 与 TestQuickCheckBackends 执行的随机序列数量，默认只执行少量序列以保证测试在数秒内完成，
+// 需要更充分的随机测试时使用 go test -run QuickCheck -quickcount=100000
+var quickCount = flag.Int("quickcount", 2000, "number of random sequences run by TestQuickCheckOperations")
+
 type randomOp struct {
 	OpType string
-	Key    string
+	Key    any
 	Value  int
 
 	Keys   []any
@@ -18,27 +23,83 @@ type randomOp struct {
 	Expend2 int
 }
 
-func operationsTest(ops []randomOp) bool {
-	table := NewTable(8)
+// operationsTest 把随机操作同时应用到表和作为参照的内置 map 上，
+// 每次操作后检查操作涉及的键与参照一致、size 与参照的大小一致；
+// 全部执行完后逐个比较所有键，检查 size 与实际占用的槽位数量一致以及表的内部结构
+func operationsTest(ops []randomOp, opts ...Option) bool {
+	table := NewTable(8, opts...)
+	model := make(map[any]int)
+
+	agree := func(key any) bool {
+		want, ok := model[key]
+		got, found := table.FindOK(key)
+		return found == ok && (!ok || got == want)
+	}
+
 	for _, op := range ops {
 		switch op.OpType {
 		case "Insert":
 			table.Insert(op.Key, op.Value)
+			model[op.Key] = op.Value
 		case "Find":
-			_ = table.Find(op.Key)
+			if !agree(op.Key) {
+				return false
+			}
 		case "Delete":
-			table.Delete(op.Key)
+			_, ok := model[op.Key]
+			if table.Delete(op.Key) != ok {
+				return false
+			}
+			delete(model, op.Key)
 		case "BatchInsert":
-			table.InsertBatch(op.Keys, op.Values)
+			if err := table.InsertBatch(op.Keys, op.Values); err != nil {
+				return false
+			}
+			for i, k := range op.Keys {
+				model[k] = op.Values[i].(int)
+			}
 		case "BatchFind":
-			_ = table.FindBatch(op.Keys)
+			results := table.FindBatch(op.Keys)
+			for i, k := range op.Keys {
+				want, ok := model[k]
+				if (ok && results[i] != want) || (!ok && results[i] != nil) {
+					return false
+				}
+			}
 		case "Shrink":
 			table.Shrink()
 		case "Expend":
 			table.Expand(op.Expend2)
 		}
+
+		if !agree(op.Key) {
+			return false
+		}
+		for _, k := range op.Keys {
+			if !agree(k) {
+				return false
+			}
+		}
+		if table.Size() != len(model) {
+			return false
+		}
 	}
-	return true
+
+	for k := range model {
+		if !agree(k) {
+			return false
+		}
+	}
+	// 扫描槽位的检查为 O(capacity)，Expend 之后容量可能很大，只在序列结束时检查一次
+	return table.Size() == table.liveCount() && table.Validate() == nil
+}
+
+// randomKey 返回较小键空间中的随机键，偶尔返回 nil，使插入、删除和查找经常命中同一个键
+func randomKey() any {
+	if rand.IntN(50) == 0 {
+		return nil
+	}
+	return fmt.Sprintf("key-%d", rand.IntN(100))
 }
 
 func generateRandomOps() []randomOp {
@@ -47,20 +108,20 @@ func generateRandomOps() []randomOp {
 
 	for i := 0; i < numOps; i++ {
 		single := func() {
-			ops[i].Key = fmt.Sprintf("key-%d", rand.IntN(100))
+			ops[i].Key = randomKey()
 			ops[i].Value = rand.Int()
 		}
 		multi := func() {
-			t := rand.UintN(3)
+			t := rand.UintN(5)
 			ops[i].Keys = make([]any, 0, t)
 			ops[i].Values = make([]any, 0, t)
-			for j := range t {
-				ops[i].Keys = append(ops[i].Keys, fmt.Sprintf("key-%d-%d", j, rand.IntN(100)))
+			for range t {
+				ops[i].Keys = append(ops[i].Keys, randomKey())
 				ops[i].Values = append(ops[i].Values, rand.Int())
 			}
 		}
 
-		switch rand.IntN(5) {
+		switch rand.IntN(7) {
 		case 0:
 			ops[i].OpType = "Insert"
 			single()
@@ -86,9 +147,14 @@ func generateRandomOps() []randomOp {
 	return ops
 }
 
+// TestQuickCheckOperations 执行随机操作序列并与参照 map 比较，序列数量由 -quickcount 设置
+// tabledebug 构建中每次修改后都会校验整张表，序列数量不超过 500
 func TestQuickCheckOperations(t *testing.T) {
 	cfg := &quick.Config{
-		MaxCount: 100000,
+		MaxCount: *quickCount,
+	}
+	if invariantChecks {
+		cfg.MaxCount = min(cfg.MaxCount, 500)
 	}
 
 	if err := quick.Check(func() bool {
@@ -98,3 +164,27 @@ func TestQuickCheckOperations(t *testing.T) {
 		t.Error("Random sequence test failed:", err)
 	}
 }
+
+// TestQuickCheckBackends 在其它冲突解决方式下执行同样的随机操作，每种方式的序列数量为 -quickcount 的 1/4
+func TestQuickCheckBackends(t *testing.T) {
+	for name, opt := range map[string]Option{
+		"chaining":  WithChaining(),
+		"cuckoo":    WithCuckoo(),
+		"quadratic": WithProbe(ProbeQuadratic),
+		"in place":  WithInPlaceResize(),
+	} {
+		cfg := &quick.Config{
+			MaxCount: max(*quickCount/4, 1),
+		}
+		if invariantChecks {
+			cfg.MaxCount = min(cfg.MaxCount, 125)
+		}
+
+		if err := quick.Check(func() bool {
+			ops := generateRandomOps()
+			return operationsTest(ops, opt)
+		}, cfg); err != nil {
+			t.Errorf("Random sequence test failed with %s: %v", name, err)
+		}
+	}
+}
@@ -1,11 +1,14 @@
 package table
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"math/rand/v2"
 	"sync"
 	"testing"
 	"time"
+	"unsafe"
 
 	"github.com/agiledragon/gomonkey/v2"
 )
@@ -164,9 +167,19 @@ func TestHighConflictScenario(t *testing.T) {
 	}
 }
 
+// skipUnderInvariantChecks 在 tabledebug 构建中跳过大规模测试：每次修改后都会校验整张表，
+// 大量插入的耗时变为平方级，超出测试的默认超时
+func skipUnderInvariantChecks(t *testing.T) {
+	t.Helper()
+	if invariantChecks {
+		t.Skip("tabledebug 构建中跳过大规模测试")
+	}
+}
+
 // 场景 5：大规模插入和扩容
 // - 插入 10 万个随机键值对,验证性能和数据正确性
 func TestMassiveInsertAndResize(t *testing.T) {
+	skipUnderInvariantChecks(t)
 	table := NewTable(16)
 	count := 100000
 
@@ -239,6 +252,39 @@ func TestDeleteAndReuse(t *testing.T) {
 	}
 }
 
+// TestDeleteWithHint 测试墓碑超过容量的 1/4 后建议整理
+func TestDeleteWithHint(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 48; i++ {
+		table.Insert(i, i)
+	}
+	if table.Capacity() != 64 {
+		t.Fatalf("期望容量为 64, 实际为 %d", table.Capacity())
+	}
+
+	// 删除 16 个后墓碑数等于容量的 1/4, 第 17 个开始超过
+	for i := 0; i < 17; i++ {
+		deleted, hint := table.DeleteWithHint(i)
+		if !deleted {
+			t.Fatalf("删除 %d 失败", i)
+		}
+		if hint != (i == 16) {
+			t.Errorf("删除第 %d 个后期望建议为 %v, 实际为 %v", i+1, i == 16, hint)
+		}
+	}
+	if table.DeletedCount() != 17 {
+		t.Errorf("DeleteWithHint 不应自动整理, 墓碑数为 %d", table.DeletedCount())
+	}
+	if deleted, hint := table.DeleteWithHint("missing"); deleted || hint {
+		t.Errorf("删除不存在的键期望返回 (false, false), 实际为 (%v, %v)", deleted, hint)
+	}
+
+	table.Compact()
+	if _, hint := table.DeleteWithHint(40); hint {
+		t.Errorf("整理后不应再建议整理")
+	}
+}
+
 // 场景 7：探测链完整性
 // - 构造特殊场景（插入、删除、再插入）验证探测链是否保持完整
 func TestProbeChainIntegrity(t *testing.T) {
@@ -416,6 +462,337 @@ func TestBatchInsertFindEdgeCases(t *testing.T) {
 	}
 }
 
+func TestFindBatchOK(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("a", 1)
+	table.Insert("b", nil)
+	table.Insert(nil, "nil key")
+
+	keys := []any{"a", "b", "c", nil, "d"}
+	values, found := table.FindBatchOK(keys)
+	wantValues := []any{1, nil, nil, "nil key", nil}
+	wantFound := []bool{true, true, false, true, false}
+	if len(values) != len(keys) || len(found) != len(keys) {
+		t.Fatalf("返回长度错误, values=%d, found=%d", len(values), len(found))
+	}
+	for i := range keys {
+		if values[i] != wantValues[i] || found[i] != wantFound[i] {
+			t.Errorf("key=%v 期望 (%v, %v), 实际为 (%v, %v)", keys[i], wantValues[i], wantFound[i], values[i], found[i])
+		}
+	}
+
+	empty := NewTable(8)
+	values, found = empty.FindBatchOK([]any{"a", "b"})
+	if len(values) != 2 || found[0] || found[1] {
+		t.Errorf("空表批量查找应全部不存在, 实际为 %v %v", values, found)
+	}
+}
+
+func TestContainsBatch(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("a", 1)
+	table.Insert("b", nil)
+	table.Insert(nil, "nil key")
+	table.Insert("c", 3)
+	table.Delete("c")
+
+	keys := []any{"a", "b", "c", nil, "d", "a"}
+	found := table.ContainsBatch(keys)
+	want := []bool{true, true, false, true, false, true}
+	if fmt.Sprint(found) != fmt.Sprint(want) {
+		t.Errorf("期望 %v, 实际为 %v", want, found)
+	}
+
+	for _, keys := range [][]any{nil, {}} {
+		if r := table.ContainsBatch(keys); r == nil || len(r) != 0 {
+			t.Errorf("ContainsBatch(%#v) 期望返回长度为 0 的切片, 实际为 %#v", keys, r)
+		}
+	}
+	if r := NewTable(8).ContainsBatch([]any{"a"}); len(r) != 1 || r[0] {
+		t.Errorf("空表期望全部不存在, 实际为 %v", r)
+	}
+}
+
+func TestGetOrCompute(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("a", 1)
+	table.Insert("b", nil)
+
+	calls := 0
+	factory := func() any {
+		calls++
+		return "computed"
+	}
+
+	if v, computed := table.GetOrCompute("a", factory); v != 1 || computed {
+		t.Errorf("已存在的键期望返回 (1, false), 实际为 (%v, %v)", v, computed)
+	}
+	if v, computed := table.GetOrCompute("b", factory); v != nil || computed {
+		t.Errorf("存储 nil 的键期望返回 (nil, false), 实际为 (%v, %v)", v, computed)
+	}
+	if calls != 0 {
+		t.Errorf("键已存在时不应调用 factory, 实际调用 %d 次", calls)
+	}
+
+	if v, computed := table.GetOrCompute("c", factory); v != "computed" || !computed {
+		t.Errorf("不存在的键期望返回 (computed, true), 实际为 (%v, %v)", v, computed)
+	}
+	if calls != 1 || table.Find("c") != "computed" {
+		t.Errorf("factory 应被调用一次且结果被插入, calls=%d", calls)
+	}
+	if v, computed := table.GetOrCompute("c", factory); v != "computed" || computed || calls != 1 {
+		t.Errorf("再次获取不应重新计算, 实际为 (%v, %v), calls=%d", v, computed, calls)
+	}
+
+	if v, inserted := table.GetOrInsert("d", 4); v != 4 || !inserted {
+		t.Errorf("GetOrInsert 期望插入 (4, true), 实际为 (%v, %v)", v, inserted)
+	}
+	if v, inserted := table.GetOrInsert("d", 5); v != 4 || inserted {
+		t.Errorf("GetOrInsert 期望返回已有的 (4, false), 实际为 (%v, %v)", v, inserted)
+	}
+}
+
+func TestMemoryBytes(t *testing.T) {
+	table := NewTable(8)
+	before := table.MemoryBytes()
+	if before < 8*int(unsafe.Sizeof(Entry{})) {
+		t.Errorf("估算值 %d 不应小于底层数组的大小", before)
+	}
+
+	for i := 0; i < 100; i++ {
+		table.Insert(i, i)
+	}
+	if table.ResizeCount() == 0 {
+		t.Fatalf("插入 100 个键应触发扩容")
+	}
+	if after := table.MemoryBytes(); after <= before {
+		t.Errorf("扩容后估算值应增长, 扩容前 %d, 扩容后 %d", before, after)
+	}
+}
+
+func TestGetOrInsertBatch(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("a", 1)
+	table.Insert("c", 3)
+
+	keys := []any{"a", "b", "c", "d", "b"}
+	values := []any{10, 20, 30, 40, 50}
+	actuals, loaded := table.GetOrInsertBatch(keys, values)
+
+	wantActuals := []any{1, 20, 3, 40, 20}
+	wantLoaded := []bool{true, false, true, false, true}
+	for i := range keys {
+		if actuals[i] != wantActuals[i] || loaded[i] != wantLoaded[i] {
+			t.Errorf("key=%v 期望 (%v, %v), 实际为 (%v, %v)", keys[i], wantActuals[i], wantLoaded[i], actuals[i], loaded[i])
+		}
+	}
+	if table.Size() != 4 || table.Find("b") != 20 || table.Find("a") != 1 {
+		t.Errorf("批量获取或插入后表的内容错误: %v", table.ToMap())
+	}
+
+	// 按传入数量预先扩容，逐个插入时不再扩容
+	table = NewTable(8)
+	keys, values = make([]any, 100), make([]any, 100)
+	for i := range keys {
+		keys[i], values[i] = i, i
+	}
+	var capacities []int
+	table.OnInsert(func(key, value any) {
+		capacities = append(capacities, table.Capacity())
+	})
+	table.GetOrInsertBatch(keys, values)
+	if table.Size() != 100 {
+		t.Errorf("size 期望为 100, 实际为 %d", table.Size())
+	}
+	for i, c := range capacities {
+		if c != table.Capacity() {
+			t.Fatalf("第 %d 次插入时容量为 %d, 期望已预先扩容到 %d", i, c, table.Capacity())
+		}
+	}
+}
+
+func TestTombstoneChurn(t *testing.T) {
+	table := NewTable(64)
+	table.hashFn = func(key any) uint64 { return mix64(uint64(key.(int))) }
+
+	// 每轮插入 10 个新键再删除其中 9 个，删除标记不断累积
+	next := 0
+	for round := 0; round < 500; round++ {
+		for i := 0; i < 10; i++ {
+			table.Insert(next+i, i)
+		}
+		for i := 1; i < 10; i++ {
+			table.Delete(next + i)
+		}
+		next += 10
+
+		// 不存在的键探测到空槽位为止，探测长度应保持有界
+		if _, d := table.probeSlot(table.hash(-1), -1, false); d > table.Capacity()/2 {
+			t.Fatalf("第 %d 轮查找不存在的键探测了 %d 步, 容量为 %d", round, d, table.Capacity())
+		}
+		if float64(table.size+table.deleted) > float64(table.Capacity())*table.LoadFactor() {
+			t.Fatalf("第 %d 轮 size+deleted=%d 超过负载上限", round, table.size+table.deleted)
+		}
+	}
+
+	for i := 0; i < next; i += 10 {
+		if table.Find(i) != 0 {
+			t.Errorf("key=%d 查找失败", i)
+		}
+	}
+	if table.Size() != 500 {
+		t.Errorf("size 期望为 500, 实际为 %d", table.Size())
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithInPlaceResize()}} {
+		table := NewTable(64, opts...)
+		for i := 0; i < 40; i++ {
+			table.Insert(i, i)
+		}
+		for i := 0; i < 30; i++ {
+			table.Delete(i)
+		}
+
+		resizes := table.ResizeCount()
+		table.Compact()
+		if table.deleted != 0 || table.Capacity() != 64 || table.ResizeCount() != resizes {
+			t.Errorf("Compact 后期望无删除标记且容量不变, deleted=%d, capacity=%d", table.deleted, table.Capacity())
+		}
+		for i := 30; i < 40; i++ {
+			if table.Find(i) != i {
+				t.Errorf("Compact 后 key=%d 查找失败", i)
+			}
+		}
+		if err := table.Validate(); err != nil {
+			t.Errorf("校验失败: %v", err)
+		}
+	}
+}
+
+func TestIncomparableKeyNoPanic(t *testing.T) {
+	table := NewTable(8)
+	// 恒定哈希保证切片键之间一定会进行比较
+	table.hashFn = func(any) uint64 { return 0 }
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("无法比较的键不应导致 panic: %v", r)
+		}
+	}()
+
+	table.Insert([]int{1, 2}, "a")
+	table.Insert([]int{1, 2}, "b")
+	table.Insert("x", 1)
+
+	// 无法比较的键互不相等，每次插入都占用新的槽位
+	if table.Size() != 3 {
+		t.Errorf("期望 size=3, 实际为 %d", table.Size())
+	}
+	if table.Find([]int{1, 2}) != nil || table.Delete([]int{1, 2}) {
+		t.Errorf("无法比较的键应无法查找或删除")
+	}
+	if table.Find("x") != 1 {
+		t.Errorf("其它键不受影响, 实际为 %v", table.Find("x"))
+	}
+}
+
+func TestInsertIfAbsent(t *testing.T) {
+	table := NewTable(8)
+
+	if !table.InsertIfAbsent("a", 1) {
+		t.Errorf("不存在的键应插入成功")
+	}
+	if table.Find("a") != 1 || table.Size() != 1 {
+		t.Errorf("插入后期望 a=1, size=1, 实际为 %v, %d", table.Find("a"), table.Size())
+	}
+
+	if table.InsertIfAbsent("a", 2) {
+		t.Errorf("已存在的键不应插入")
+	}
+	if table.Find("a") != 1 || table.Size() != 1 {
+		t.Errorf("已存在的键不应被修改, 实际为 %v, %d", table.Find("a"), table.Size())
+	}
+
+	table.Insert("b", nil)
+	if table.InsertIfAbsent("b", 3) || table.Find("b") != nil {
+		t.Errorf("存储 nil 的键同样视为已存在")
+	}
+}
+
+func TestReplaceIfPresent(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("a", 1)
+
+	if !table.ReplaceIfPresent("a", 2) {
+		t.Errorf("已存在的键应替换成功")
+	}
+	if table.Find("a") != 2 || table.Size() != 1 {
+		t.Errorf("替换后期望 a=2, size=1, 实际为 %v, %d", table.Find("a"), table.Size())
+	}
+
+	if table.ReplaceIfPresent("b", 3) {
+		t.Errorf("不存在的键不应替换")
+	}
+	if table.Contains("b") || table.Size() != 1 {
+		t.Errorf("不存在的键不应被插入, size=%d", table.Size())
+	}
+}
+
+// TestUpsert 测试使用求和函数累加重复插入的计数
+func TestUpsert(t *testing.T) {
+	table := NewTable(8)
+	sum := func(existing, incoming any) any { return existing.(int) + incoming.(int) }
+
+	words := []string{"a", "b", "a", "c", "a", "b"}
+	for _, w := range words {
+		table.Upsert(w, 1, sum)
+	}
+	expected := map[string]int{"a": 3, "b": 2, "c": 1}
+	for k, n := range expected {
+		if v := table.Find(k); v != n {
+			t.Errorf("%s 期望计数为 %d, 实际为 %v", k, n, v)
+		}
+	}
+	if table.Size() != 3 {
+		t.Errorf("期望 size=3, 实际为 %d", table.Size())
+	}
+
+	if v := table.Upsert("a", 10, sum); v != 13 {
+		t.Errorf("Upsert 期望返回合并后的 13, 实际为 %v", v)
+	}
+	// 键不存在时不调用 combine
+	if v := table.Upsert("d", 5, func(any, any) any { panic("不应调用 combine") }); v != 5 {
+		t.Errorf("新键期望返回 5, 实际为 %v", v)
+	}
+}
+
+func TestInsertAfterTombstoneNoDuplicate(t *testing.T) {
+	table := NewTable(8)
+	table.hashFn = func(any) uint64 { return 0 }
+
+	table.Insert("a", 1)
+	table.Insert("b", 2)
+	table.Delete("a")
+	// b 位于删除标记之后，更新时不能复用删除标记插入第二份
+	table.Insert("b", 3)
+
+	if table.Size() != 1 || table.liveCount() != 1 {
+		t.Errorf("期望 size=1, 实际 size=%d, 占用槽位 %d", table.Size(), table.liveCount())
+	}
+	table.Delete("b")
+	if table.Contains("b") {
+		t.Errorf("删除后不应再找到 b")
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+}
+
 func TestNilKey(t *testing.T) {
 	table := NewTable(8)
 
@@ -474,6 +851,52 @@ func TestConcurrentRead(t *testing.T) {
 	}
 }
 
+// TestExpandOverflow 测试容量接近上限时不会溢出, 只比较容量不实际分配
+func TestExpandOverflow(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("a", 1)
+
+	// 不超过 maxCapacity 但超出运行时单次分配上限的容量同样返回错误, 而不是在分配数组时 panic
+	for _, n := range []int{maxCapacity + 1, math.MaxInt, maxCapacity, 1 << 45} {
+		if err := table.TryExpand(n); !errors.Is(err, ErrCapacityOverflow) {
+			t.Errorf("TryExpand(%d) 期望返回 ErrCapacityOverflow, 实际为 %v", n, err)
+		}
+	}
+	if table.Capacity() != 8 || table.Find("a") != 1 || table.Validate() != nil {
+		t.Errorf("TryExpand 失败后表不应改变, 容量为 %d", table.Capacity())
+	}
+	for name, opt := range map[string]Option{"in place": WithInPlaceResize(), "chaining": WithChaining()} {
+		other := NewTable(8, opt)
+		other.Insert("a", 1)
+		if err := other.TryExpand(maxCapacity); !errors.Is(err, ErrCapacityOverflow) || other.Find("a") != 1 || other.Validate() != nil {
+			t.Errorf("%s: TryExpand(maxCapacity) 期望返回 ErrCapacityOverflow 且表不变, 实际为 %v", name, err)
+		}
+	}
+	if err := table.TryExpand(64); err != nil || table.Capacity() != 64 {
+		t.Errorf("TryExpand(64) 期望成功, 错误为 %v, 容量为 %d", err, table.Capacity())
+	}
+
+	func() {
+		defer func() {
+			if err, _ := recover().(error); !errors.Is(err, ErrCapacityOverflow) {
+				t.Errorf("Expand 超过上限期望以 ErrCapacityOverflow panic, 实际为 %v", err)
+			}
+		}()
+		table.Expand(math.MaxInt)
+	}()
+
+	// 增长后的容量被限制在上限以内
+	for _, f := range []float64{1.5, 2, 100} {
+		huge := NewTable(8, WithGrowthFactor(f))
+		for _, capacity := range []int{maxCapacity / 2, maxCapacity - 1, maxCapacity} {
+			huge.capacity = capacity
+			if next := huge.nextCapacity(); next <= 0 || next > maxCapacity {
+				t.Errorf("f=%v, 容量 %d 的下一个容量 %d 越界", f, capacity, next)
+			}
+		}
+	}
+}
+
 // 测试 Expand 方法
 func TestExpand(t *testing.T) {
 	table := NewTable(8)
@@ -538,3 +961,1040 @@ func TestShrink(t *testing.T) {
 		}
 	}
 }
+
+// TestShrinkToFit 测试大量删除后缩小到恰好容纳剩余键值对的最小容量
+func TestShrinkToFit(t *testing.T) {
+	for _, tc := range []struct {
+		opts     []Option
+		expected int
+	}{
+		// ceil(5 / 0.75) = 7, 受默认最小容量 8 限制
+		{nil, 8},
+		{[]Option{WithMinCapacity(1)}, 7},
+		{[]Option{WithMinCapacity(1), func(st *Table) { st.loadFactor = 0.5 }}, 10},
+	} {
+		table := NewTable(8, tc.opts...)
+		for i := 0; i < 100; i++ {
+			table.Insert(i, i)
+		}
+		for i := 0; i < 95; i++ {
+			table.Delete(i)
+		}
+
+		table.ShrinkToFit()
+		if table.Capacity() != tc.expected {
+			t.Errorf("期望容量为 %d, 实际为 %d", tc.expected, table.Capacity())
+		}
+		if table.DeletedCount() != 0 {
+			t.Errorf("ShrinkToFit 后不应留下墓碑, 实际为 %d", table.DeletedCount())
+		}
+		for i := 95; i < 100; i++ {
+			if v := table.Find(i); v != i {
+				t.Errorf("查找 %d 失败, 返回 %v", i, v)
+			}
+		}
+
+		// 已经是最小容量时不再调整
+		count := table.ResizeCount()
+		table.ShrinkToFit()
+		if table.ResizeCount() != count {
+			t.Errorf("容量已经最小时不应再调整")
+		}
+	}
+}
+
+// TestClear 测试 Clear 删除所有键值对并保留容量
+func TestClear(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"linear":   {WithInsertionOrder(), WithMaxEntries(1000)},
+		"chaining": {WithChaining()},
+		"cuckoo":   {WithCuckoo()},
+		"bloom":    {WithBloomFilter(100, 0.01)},
+	} {
+		table := NewTable(8, opts...)
+		for i := 0; i < 500; i++ {
+			table.Insert(i, i)
+		}
+		table.Delete(0)
+		deleted := 0
+		table.OnDelete(func(key, value any) {
+			if key != value {
+				t.Errorf("%s: OnDelete 传入的键值不匹配, %v != %v", name, key, value)
+			}
+			deleted++
+		})
+		snap := table.Snapshot()
+		capacity := table.Capacity()
+
+		table.Clear()
+		if table.Size() != 0 || table.DeletedCount() != 0 || table.Capacity() != capacity {
+			t.Errorf("%s: Clear 后期望为空且容量为 %d, 实际 size=%d, 容量=%d", name, capacity, table.Size(), table.Capacity())
+		}
+		if deleted != 499 {
+			t.Errorf("%s: 期望触发 499 次 OnDelete, 实际为 %d", name, deleted)
+		}
+		if snap.Size() != 499 || snap.Find(1) != 1 {
+			t.Errorf("%s: Clear 不应影响快照", name)
+		}
+		if table.Find(1) != nil || len(table.Keys()) != 0 {
+			t.Errorf("%s: Clear 后不应找到任何键", name)
+		}
+
+		for i := 0; i < 100; i++ {
+			table.Insert(i, i*2)
+		}
+		for i := 0; i < 100; i++ {
+			if v := table.Find(i); v != i*2 {
+				t.Errorf("%s: 重新插入后查找 %d 失败, 返回 %v", name, i, v)
+			}
+		}
+		if table.Capacity() != capacity {
+			t.Errorf("%s: 重新插入不应扩容", name)
+		}
+		if err := table.Validate(); err != nil {
+			t.Errorf("%s: 校验失败: %v", name, err)
+		}
+	}
+}
+
+// TestTrim 测试 Trim 把容量缩小到最小并重新分配底层数组
+func TestTrim(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithInPlaceResize()}} {
+		table := NewTable(8, opts...)
+		for i := 0; i < 1000; i++ {
+			table.Insert(i, i)
+		}
+		table.Clear()
+		before := &table.entries[0]
+
+		table.Trim()
+		if table.Capacity() != 8 {
+			t.Errorf("Trim 后期望容量为最小容量 8, 实际为 %d", table.Capacity())
+		}
+		if cap(table.entries) != 8 || cap(table.metas) != 8 || &table.entries[0] == before {
+			t.Errorf("Trim 后期望分配新的小数组, 实际 cap=%d", cap(table.entries))
+		}
+
+		// 原地缩容沿用原来的大数组, Trim 重新分配
+		for i := 0; i < 1000; i++ {
+			table.Insert(i, i)
+		}
+		for i := 0; i < 990; i++ {
+			table.Delete(i)
+		}
+		table.Shrink()
+		table.Trim()
+		if cap(table.entries) != table.Capacity() {
+			t.Errorf("Trim 后底层数组期望与容量相同, cap=%d, 容量=%d", cap(table.entries), table.Capacity())
+		}
+		for i := 990; i < 1000; i++ {
+			if v := table.Find(i); v != i {
+				t.Errorf("Trim 后查找 %d 失败, 返回 %v", i, v)
+			}
+		}
+
+		count := table.ResizeCount()
+		table.Trim()
+		if table.ResizeCount() != count {
+			t.Errorf("已经是最小容量时 Trim 不应再调整")
+		}
+	}
+}
+
+// 测试 Shrink 保留负载因子和哈希函数等配置
+func TestShrinkPreservesTunables(t *testing.T) {
+	table := NewTable(8, WithGrowthFactor(3))
+	if err := table.SetLoadFactor(0.5); err != nil {
+		t.Fatalf("设置负载因子失败: %v", err)
+	}
+	calls := 0
+	table.hashFn = func(key any) uint64 {
+		calls++
+		return uint64(key.(int))
+	}
+
+	for i := 0; i < 100; i++ {
+		table.Insert(i, i)
+	}
+	for i := 0; i < 90; i++ {
+		table.Delete(i)
+	}
+	table.Shrink()
+
+	if table.LoadFactor() != 0.5 {
+		t.Errorf("缩容后负载因子期望为 0.5, 实际为 %v", table.LoadFactor())
+	}
+	if table.Capacity() != 20 {
+		t.Errorf("缩容后容量期望为 10/0.5=20, 实际为 %d", table.Capacity())
+	}
+	if table.growthFactor != 3 {
+		t.Errorf("缩容后增长倍数期望为 3, 实际为 %v", table.growthFactor)
+	}
+
+	calls = 0
+	for i := 90; i < 100; i++ {
+		if table.Find(i) != i {
+			t.Errorf("缩容后 key=%d 查找失败", i)
+		}
+	}
+	if calls == 0 {
+		t.Errorf("缩容后应继续使用自定义哈希函数")
+	}
+
+	// 按 0.5 的负载因子，容量 20 最多容纳 10 个键
+	table.Insert(100, 100)
+	if table.Capacity() != 60 {
+		t.Errorf("超过 0.5 的负载后期望按 3 倍扩容到 60, 实际为 %d", table.Capacity())
+	}
+}
+
+// 测试 Keys 与 Values 方法
+func TestKeysAndValues(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 30; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	for i := 0; i < 30; i += 3 {
+		table.Delete(fmt.Sprintf("key-%d", i))
+	}
+
+	keys := table.Keys()
+	values := table.Values()
+	if len(keys) != table.Size() {
+		t.Errorf("期望 len(Keys())=%d, 实际为 %d", table.Size(), len(keys))
+	}
+	if len(values) != table.Size() {
+		t.Errorf("期望 len(Values())=%d, 实际为 %d", table.Size(), len(values))
+	}
+
+	seen := make(map[any]bool)
+	for _, k := range keys {
+		if seen[k] {
+			t.Errorf("Keys 返回了重复的键 %v", k)
+		}
+		seen[k] = true
+		if table.Find(k) == nil {
+			t.Errorf("Keys 返回了不存在的键 %v", k)
+		}
+	}
+
+	sum := 0
+	for _, v := range values {
+		sum += v.(int)
+	}
+	expected := 0
+	for i := 0; i < 30; i++ {
+		if i%3 != 0 {
+			expected += i
+		}
+	}
+	if sum != expected {
+		t.Errorf("Values 之和期望为 %d, 实际为 %d", expected, sum)
+	}
+}
+
+// TestSortedKeys 测试按字典序排序字符串键
+func TestSortedKeys(t *testing.T) {
+	table := NewTable(8)
+	for _, k := range []string{"pear", "apple", "fig", "banana", "cherry"} {
+		table.Insert(k, len(k))
+	}
+	table.Delete("fig")
+
+	keys := table.SortedKeys(func(a, b any) bool { return a.(string) < b.(string) })
+	if fmt.Sprint(keys) != "[apple banana cherry pear]" {
+		t.Errorf("期望按字典序排序, 实际为 %v", keys)
+	}
+
+	if keys := NewTable(8).SortedKeys(func(a, b any) bool { return false }); len(keys) != 0 {
+		t.Errorf("空表期望返回空切片, 实际为 %v", keys)
+	}
+}
+
+// 测试 ToMap 与 NewFromMap 的相互转换
+func TestToMapFromMap(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 100; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	table.Insert(nil, "nil-value")
+	table.Delete("key-7")
+
+	m := table.ToMap()
+	if len(m) != table.Size() {
+		t.Errorf("期望 len(ToMap())=%d, 实际为 %d", table.Size(), len(m))
+	}
+	if _, ok := m["key-7"]; ok {
+		t.Errorf("ToMap 不应包含已删除的键")
+	}
+
+	restored := NewFromMap(m)
+	if !restored.Equal(table, nil) {
+		t.Errorf("NewFromMap(ToMap()) 应与原表相等")
+	}
+
+	// 预先分配后加载过程中不应再扩容
+	if restored.Capacity() != 134 {
+		t.Errorf("NewFromMap 应预先分配容量 134, 实际为 %d", restored.Capacity())
+	}
+
+	if NewFromMap(nil).Size() != 0 {
+		t.Errorf("NewFromMap(nil) 应返回空表")
+	}
+}
+
+// TestToMapIncomparableKeys 测试无法作为 map 键的键被跳过而不是 panic
+func TestToMapIncomparableKeys(t *testing.T) {
+	type pathKey struct {
+		parts []string
+	}
+	table := NewTable(8, WithKeyEqual(func(a, b any) bool { return fmt.Sprint(a) == fmt.Sprint(b) }))
+	table.Insert(pathKey{[]string{"a", "b"}}, 1)
+	table.Insert("plain", 2)
+	table.Insert(nil, 3)
+
+	m := table.ToMap()
+	if len(m) != 2 || m["plain"] != 2 || m[nil] != 3 {
+		t.Errorf("期望只包含可作为 map 键的键, 实际为 %v", m)
+	}
+}
+
+// TestNewTableFromPairs 测试根据键值对创建表
+func TestNewTableFromPairs(t *testing.T) {
+	table := NewTableFromPairs(
+		Pair{"a", 1},
+		Pair{"b", 2},
+		Pair{3, "c"},
+		Pair{nil, "nil-value"},
+		Pair{"a", 5},
+	)
+
+	expected := map[any]any{"a": 5, "b": 2, 3: "c", nil: "nil-value"}
+	if table.Size() != len(expected) {
+		t.Errorf("期望 size=%d, 实际为 %d", len(expected), table.Size())
+	}
+	for k, v := range expected {
+		if got := table.Find(k); got != v {
+			t.Errorf("查找 %v 期望为 %v, 实际为 %v", k, v, got)
+		}
+	}
+	if table.ResizeCount() != 0 {
+		t.Errorf("预先分配后不应扩容, 实际扩容 %d 次", table.ResizeCount())
+	}
+
+	if NewTableFromPairs().Size() != 0 {
+		t.Errorf("没有键值对时应返回空表")
+	}
+}
+
+// TestPutAll 测试按顺序插入键值对, 重复的键以最后一次为准
+func TestPutAll(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("a", 0)
+
+	pairs := []Pair{{"a", 1}, {"b", 2}, {"a", 3}}
+	for i := 0; i < 100; i++ {
+		pairs = append(pairs, Pair{i, i})
+	}
+	pairs = append(pairs, Pair{"b", 4})
+
+	table.PutAll(pairs)
+	if v := table.Find("a"); v != 3 {
+		t.Errorf("a 期望为最后一次的 3, 实际为 %v", v)
+	}
+	if v := table.Find("b"); v != 4 {
+		t.Errorf("b 期望为最后一次的 4, 实际为 %v", v)
+	}
+	if table.Size() != 102 {
+		t.Errorf("期望 size=102, 实际为 %d", table.Size())
+	}
+	// 预先扩容一次, 之后的插入不再扩容
+	if table.ResizeCount() != 1 {
+		t.Errorf("期望只扩容 1 次, 实际为 %d", table.ResizeCount())
+	}
+
+	table.PutAll(nil)
+	if table.Size() != 102 {
+		t.Errorf("空切片不应修改表")
+	}
+}
+
+// TestInsertBatchResult 测试批量插入逐个报告错误, 只有无法比较的键对应位置出错
+func TestInsertBatchResult(t *testing.T) {
+	table := NewTable(8)
+	keys := []any{"a", []int{1}, "c"}
+	errs := table.InsertBatchResult(keys, []any{1, 2, 3})
+	if len(errs) != len(keys) {
+		t.Fatalf("期望返回 %d 个错误, 实际为 %d", len(keys), len(errs))
+	}
+	if errs[0] != nil || errs[2] != nil {
+		t.Errorf("可比较的键不应出错, 实际为 %v, %v", errs[0], errs[2])
+	}
+	if !errors.Is(errs[1], ErrNotComparable) {
+		t.Errorf("切片键期望返回 ErrNotComparable, 实际为 %v", errs[1])
+	}
+	if table.Size() != 2 || table.Find("a") != 1 || table.Find("c") != 3 {
+		t.Errorf("其余键应照常插入, size=%d", table.Size())
+	}
+
+	strict := NewTable(8, WithDuplicateBatchPolicy(DuplicateError))
+	errs = strict.InsertBatchResult([]any{"x", "y", "x"}, []any{1, 2, 3})
+	if errs[0] != nil || errs[1] != nil || !errors.Is(errs[2], ErrDuplicateKey) {
+		t.Errorf("只有重复的键应返回 ErrDuplicateKey, 实际为 %v", errs)
+	}
+	if strict.Find("x") != 1 {
+		t.Errorf("重复的键不应覆盖第一次的值, 实际为 %v", strict.Find("x"))
+	}
+
+	table.Freeze()
+	for i, err := range table.InsertBatchResult([]any{"d"}, []any{4}) {
+		if !errors.Is(err, ErrFrozen) {
+			t.Errorf("冻结的表第 %d 个键期望返回 ErrFrozen, 实际为 %v", i, err)
+		}
+	}
+}
+
+// 测试 IsEmpty 与 Len 方法
+func TestIsEmptyAndLen(t *testing.T) {
+	table := NewTable(8)
+	if !table.IsEmpty() || table.Len() != 0 {
+		t.Errorf("新建的表应为空, IsEmpty=%v, Len=%d", table.IsEmpty(), table.Len())
+	}
+
+	table.Insert("a", 1)
+	table.Insert("b", 2)
+	if table.IsEmpty() || table.Len() != 2 {
+		t.Errorf("插入后 IsEmpty=%v, Len=%d", table.IsEmpty(), table.Len())
+	}
+
+	// 删除后只剩下删除标记, 不应计入
+	table.Delete("a")
+	table.Delete("b")
+	if !table.IsEmpty() || table.Len() != 0 {
+		t.Errorf("全部删除后应为空, IsEmpty=%v, Len=%d", table.IsEmpty(), table.Len())
+	}
+}
+
+// 测试 Reserve 方法
+func TestReserve(t *testing.T) {
+	table := NewTable(8)
+	table.Reserve(1000)
+	capacity := table.Capacity()
+	if float64(capacity)*0.75 < 1000 {
+		t.Errorf("Reserve(1000) 后容量不足, 实际为 %d", capacity)
+	}
+
+	for i := 0; i < 1000; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+		if table.Capacity() != capacity {
+			t.Fatalf("Reserve 后插入第 %d 个元素时发生了扩容, 容量 %d -> %d", i+1, capacity, table.Capacity())
+		}
+	}
+
+	// 容量已足够时为空操作
+	table.Reserve(10)
+	if table.Capacity() != capacity {
+		t.Errorf("容量足够时 Reserve 不应改变容量, 期望 %d, 实际为 %d", capacity, table.Capacity())
+	}
+
+	for i := 0; i < 1000; i++ {
+		if v := table.Find(fmt.Sprintf("key-%d", i)); v != i {
+			t.Errorf("查找 key-%d 失败, 返回 %v", i, v)
+		}
+	}
+}
+
+// TestGrow 测试 Grow 之后插入 additional 个新键不会扩容
+func TestGrow(t *testing.T) {
+	table := NewTable(8)
+	for i := 0; i < 100; i++ {
+		table.Insert(i, i)
+	}
+	// 留下墓碑, Grow 需要把它们也算进负载
+	for i := 0; i < 20; i++ {
+		table.Delete(i)
+	}
+	if table.deleted == 0 {
+		t.Fatalf("删除后期望留下墓碑")
+	}
+
+	table.Grow(500)
+	count := table.ResizeCount()
+	for i := 100; i < 600; i++ {
+		table.Insert(i, i)
+	}
+	if table.ResizeCount() != count {
+		t.Errorf("Grow(500) 后插入 500 个键发生了 %d 次扩容", table.ResizeCount()-count)
+	}
+
+	// 容量足够时为空操作
+	capacity := table.Capacity()
+	table.Grow(0)
+	if table.Capacity() != capacity || table.ResizeCount() != count {
+		t.Errorf("容量足够时 Grow 不应扩容")
+	}
+
+	for i := 20; i < 600; i++ {
+		if v := table.Find(i); v != i {
+			t.Errorf("查找 %d 失败, 返回 %v", i, v)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Grow(-1) 应 panic")
+		}
+	}()
+	table.Grow(-1)
+}
+
+// 测试 ResizeCount 与 OnResize
+func TestResizeCountAndCallback(t *testing.T) {
+	table := NewTable(8)
+
+	var events [][2]int
+	table.OnResize(func(oldCap, newCap int) {
+		events = append(events, [2]int{oldCap, newCap})
+	})
+
+	for i := 0; i < 13; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+
+	if table.ResizeCount() != 2 {
+		t.Errorf("期望扩容 2 次, 实际为 %d", table.ResizeCount())
+	}
+	expected := [][2]int{{8, 16}, {16, 32}}
+	if fmt.Sprint(events) != fmt.Sprint(expected) {
+		t.Errorf("期望回调参数为 %v, 实际为 %v", expected, events)
+	}
+
+	table.OnResize(nil)
+	table.Expand(64)
+	if table.ResizeCount() != 3 {
+		t.Errorf("期望扩容 3 次, 实际为 %d", table.ResizeCount())
+	}
+	if len(events) != 2 {
+		t.Errorf("取消回调后不应再触发, 实际触发 %d 次", len(events))
+	}
+
+	// Reserve 之后插入不应再扩容
+	reserved := NewTable(8)
+	reserved.Reserve(1000)
+	for i := 0; i < 1000; i++ {
+		reserved.Insert(i, i)
+	}
+	if reserved.ResizeCount() != 1 {
+		t.Errorf("Reserve 后插入期望只扩容 1 次, 实际为 %d", reserved.ResizeCount())
+	}
+}
+
+// 测试 FindOr 方法
+func TestFindOr(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("present", 1)
+	table.Insert("stored-nil", nil)
+
+	if v := table.FindOr("present", 100); v != 1 {
+		t.Errorf("键存在时期望返回 1, 实际为 %v", v)
+	}
+	if v := table.FindOr("absent", 100); v != 100 {
+		t.Errorf("键不存在时期望返回 fallback 100, 实际为 %v", v)
+	}
+	if v := table.FindOr("stored-nil", 100); v != nil {
+		t.Errorf("存储的值为 nil 时期望返回 nil, 实际为 %v", v)
+	}
+
+	table.Delete("present")
+	if v := table.FindOr("present", 100); v != 100 {
+		t.Errorf("删除后期望返回 fallback 100, 实际为 %v", v)
+	}
+}
+
+// TestFindPath 测试按路径在嵌套的表中逐级查找
+func TestFindPath(t *testing.T) {
+	inner := NewTable(8)
+	inner.Insert("b", 42)
+	root := NewTable(8)
+	root.Insert("a", inner)
+	root.Insert("leaf", "value")
+
+	if v, ok := root.FindPath("a", "b"); !ok || v != 42 {
+		t.Errorf("路径 a.b 期望为 42, 实际为 %v, ok=%v", v, ok)
+	}
+	if v, ok := root.FindPath("a"); !ok || v != inner {
+		t.Errorf("路径 a 期望返回内层表, 实际为 %v, ok=%v", v, ok)
+	}
+	if _, ok := root.FindPath("a", "missing"); ok {
+		t.Errorf("内层不存在的键不应找到")
+	}
+	if _, ok := root.FindPath("missing", "b"); ok {
+		t.Errorf("外层不存在的键不应找到")
+	}
+	if _, ok := root.FindPath("leaf", "b"); ok {
+		t.Errorf("中间一级不是 *Table 时不应找到")
+	}
+	if v, ok := root.FindPath(); !ok || v != root {
+		t.Errorf("没有键时期望返回表本身")
+	}
+}
+
+// 测试 Swap 方法
+func TestSwap(t *testing.T) {
+	table := NewTable(8)
+
+	previous, loaded := table.Swap("key", 1)
+	if previous != nil || loaded {
+		t.Errorf("首次插入期望返回 nil, false, 实际为 %v, %v", previous, loaded)
+	}
+
+	previous, loaded = table.Swap("key", 2)
+	if previous != 1 || !loaded {
+		t.Errorf("覆盖时期望返回 1, true, 实际为 %v, %v", previous, loaded)
+	}
+	if v := table.Find("key"); v != 2 {
+		t.Errorf("Swap 后期望值为 2, 实际为 %v", v)
+	}
+	if table.Size() != 1 {
+		t.Errorf("期望 size=1, 实际为 %d", table.Size())
+	}
+
+	// 触发扩容时依然正确
+	for i := 0; i < 100; i++ {
+		if _, loaded := table.Swap(i, i); loaded {
+			t.Errorf("首次插入 %d 不应返回 loaded=true", i)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if previous, loaded := table.Swap(i, -i); previous != i || !loaded {
+			t.Errorf("覆盖 %d 期望返回 %d, true, 实际为 %v, %v", i, i, previous, loaded)
+		}
+	}
+}
+
+// 测试 CompareAndSwap 与 CompareAndDelete
+func TestCompareAndSwapDelete(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("key", 1)
+
+	if table.CompareAndSwap("key", 2, 3, nil) {
+		t.Errorf("当前值不匹配时 CompareAndSwap 不应成功")
+	}
+	if v := table.Find("key"); v != 1 {
+		t.Errorf("不匹配时值不应改变, 实际为 %v", v)
+	}
+	if !table.CompareAndSwap("key", 1, 3, nil) {
+		t.Errorf("当前值匹配时 CompareAndSwap 应成功")
+	}
+	if v := table.Find("key"); v != 3 {
+		t.Errorf("CompareAndSwap 后期望值为 3, 实际为 %v", v)
+	}
+	if table.CompareAndSwap("absent", nil, 1, nil) {
+		t.Errorf("键不存在时 CompareAndSwap 不应成功")
+	}
+	if table.Size() != 1 {
+		t.Errorf("CompareAndSwap 不应插入新键, size=%d", table.Size())
+	}
+
+	if table.CompareAndDelete("key", 1, nil) {
+		t.Errorf("当前值不匹配时 CompareAndDelete 不应成功")
+	}
+	if !table.CompareAndDelete("key", 3, nil) {
+		t.Errorf("当前值匹配时 CompareAndDelete 应成功")
+	}
+	if table.Size() != 0 || table.Find("key") != nil {
+		t.Errorf("CompareAndDelete 后键应被删除")
+	}
+	if table.CompareAndDelete("key", 3, nil) {
+		t.Errorf("键不存在时 CompareAndDelete 不应成功")
+	}
+
+	// 无法比较的值不相等, 不会 panic
+	table.Insert("slice", []int{1, 2})
+	if table.CompareAndSwap("slice", []int{1, 2}, []int{3}, nil) {
+		t.Errorf("切片值无法用 == 比较, CompareAndSwap 不应成功")
+	}
+	if table.CompareAndDelete("slice", []int{1, 2}, nil) || !table.Contains("slice") {
+		t.Errorf("切片值无法用 == 比较, CompareAndDelete 不应成功")
+	}
+
+	// 自定义比较函数
+	sliceEq := func(a, b any) bool { return fmt.Sprint(a) == fmt.Sprint(b) }
+	if !table.CompareAndSwap("slice", []int{1, 2}, []int{3}, sliceEq) {
+		t.Errorf("使用自定义比较函数时 CompareAndSwap 应成功")
+	}
+	if !table.CompareAndDelete("slice", []int{3}, sliceEq) {
+		t.Errorf("使用自定义比较函数时 CompareAndDelete 应成功")
+	}
+}
+
+// 测试 LoadFactor 与 SetLoadFactor
+func TestSetLoadFactor(t *testing.T) {
+	table := NewTable(8)
+	if table.LoadFactor() != 0.75 {
+		t.Errorf("默认负载因子期望为 0.75, 实际为 %v", table.LoadFactor())
+	}
+
+	for i := 0; i < 12; i++ {
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	if table.Capacity() != 16 {
+		t.Fatalf("期望容量为 16, 实际为 %d", table.Capacity())
+	}
+
+	// 提高负载因子不需要调整容量
+	if err := table.SetLoadFactor(0.9); err != nil {
+		t.Fatalf("SetLoadFactor 发生错误: %v", err)
+	}
+	if table.Capacity() != 16 || table.ResizeCount() != 1 {
+		t.Errorf("提高负载因子不应扩容, 容量=%d, 扩容次数=%d", table.Capacity(), table.ResizeCount())
+	}
+
+	// 降低到 12/16 以下需要立即扩容
+	if err := table.SetLoadFactor(0.5); err != nil {
+		t.Fatalf("SetLoadFactor 发生错误: %v", err)
+	}
+	if table.LoadFactor() != 0.5 {
+		t.Errorf("负载因子期望为 0.5, 实际为 %v", table.LoadFactor())
+	}
+	if float64(table.Size()) > float64(table.Capacity())*0.5 {
+		t.Errorf("降低负载因子后应扩容, 容量=%d, size=%d", table.Capacity(), table.Size())
+	}
+	for i := 0; i < 12; i++ {
+		if v := table.Find(fmt.Sprintf("key-%d", i)); v != i {
+			t.Errorf("查找 key-%d 失败, 返回 %v", i, v)
+		}
+	}
+
+	for _, f := range []float64{0, -1, 1, 1.5} {
+		if err := table.SetLoadFactor(f); err == nil {
+			t.Errorf("负载因子 %v 应被拒绝", f)
+		}
+	}
+	if table.LoadFactor() != 0.5 {
+		t.Errorf("非法值不应修改负载因子, 实际为 %v", table.LoadFactor())
+	}
+}
+
+// 测试批量操作对 nil 切片与空切片的处理
+func TestBatchNilSlices(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("a", 1)
+
+	cases := []struct {
+		keys, values []any
+		wantErr      bool
+	}{
+		{nil, nil, false},
+		{nil, []any{}, false},
+		{[]any{}, nil, false},
+		{[]any{}, []any{}, false},
+		{nil, []any{1}, true},
+		{[]any{"k"}, nil, true},
+	}
+	for _, c := range cases {
+		err := table.InsertBatch(c.keys, c.values)
+		if (err != nil) != c.wantErr {
+			t.Errorf("InsertBatch(%v, %v) 错误期望 %v, 实际为 %v", c.keys, c.values, c.wantErr, err)
+		}
+		if table.Size() != 1 || table.Capacity() != 8 {
+			t.Errorf("InsertBatch(%v, %v) 不应修改表, size=%d, 容量=%d", c.keys, c.values, table.Size(), table.Capacity())
+		}
+	}
+
+	for _, keys := range [][]any{nil, {}} {
+		r := table.FindBatch(keys)
+		if r == nil || len(r) != 0 {
+			t.Errorf("FindBatch(%#v) 期望返回长度为 0 的切片, 实际为 %#v", keys, r)
+		}
+	}
+}
+
+// 测试批量插入失败时不会修改表
+func TestBatchInsertAtomic(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("a", 1)
+
+	keys := []any{"k1", "k2", "k3", "k4", "k5", "k6", "k7", []int{1}}
+	vals := []any{1, 2, 3, 4, 5, 6, 7, 8}
+	err := table.InsertBatch(keys, vals)
+	if !errors.Is(err, ErrNotComparable) {
+		t.Errorf("包含无法比较的键时期望返回 ErrNotComparable, 实际为 %v", err)
+	}
+	if table.Size() != 1 || table.Capacity() != 8 {
+		t.Errorf("批量插入失败后表不应改变, size=%d, 容量=%d", table.Size(), table.Capacity())
+	}
+	if v := table.Find("k1"); v != nil {
+		t.Errorf("批量插入失败后不应插入任何键, k1=%v", v)
+	}
+
+	// 设置了自定义比较函数时允许无法比较的键
+	custom := NewTable(8, WithKeyEqual(func(a, b any) bool { return fmt.Sprint(a) == fmt.Sprint(b) }))
+	if err := custom.InsertBatch(keys, vals); err != nil {
+		t.Errorf("自定义比较函数时不应报错, 实际为 %v", err)
+	}
+	if v := custom.Find([]int{1}); v != 8 {
+		t.Errorf("查找切片键失败, 返回 %v", v)
+	}
+}
+
+// TestTryInsert 测试 TryInsert 在无法插入时返回错误
+func TestTryInsert(t *testing.T) {
+	table := NewTable(8)
+	if err := table.TryInsert("a", 1); err != nil {
+		t.Errorf("插入普通键不应报错, 实际为 %v", err)
+	}
+	if v := table.Find("a"); v != 1 {
+		t.Errorf("TryInsert 后查找失败, 返回 %v", v)
+	}
+
+	err := table.TryInsert([]int{1}, 2)
+	if !errors.Is(err, ErrNotComparable) {
+		t.Errorf("无法比较的键期望返回 ErrNotComparable, 实际为 %v", err)
+	}
+	if table.Size() != 1 {
+		t.Errorf("插入失败后 size 不应改变, 实际为 %d", table.Size())
+	}
+
+	// 设置了自定义比较函数时允许无法比较的键
+	custom := NewTable(8, WithKeyEqual(func(a, b any) bool { return fmt.Sprint(a) == fmt.Sprint(b) }))
+	if err := custom.TryInsert([]int{1}, 2); err != nil {
+		t.Errorf("自定义比较函数时不应报错, 实际为 %v", err)
+	}
+
+	// 大量插入时扩容后重试, 不会因为表满失败
+	for i := 0; i < 1000; i++ {
+		if err := table.TryInsert(i, i); err != nil {
+			t.Fatalf("插入 %d 时报错: %v", i, err)
+		}
+	}
+
+	frozen := table.Snapshot()
+	if err := frozen.TryInsert("b", 3); !errors.Is(err, ErrFrozen) {
+		t.Errorf("冻结的表期望返回 ErrFrozen, 实际为 %v", err)
+	}
+	if frozen.Find("b") != nil {
+		t.Errorf("冻结的表不应被修改")
+	}
+}
+
+// 测试 OnInsert 与 OnDelete 回调
+func TestInsertDeleteHooks(t *testing.T) {
+	table := NewTable(8)
+
+	inserted := map[any]any{}
+	insertCalls, deleteCalls := 0, 0
+	var deleted []any
+	table.OnInsert(func(key, value any) {
+		insertCalls++
+		inserted[key] = value
+	})
+	table.OnDelete(func(key, value any) {
+		deleteCalls++
+		deleted = append(deleted, fmt.Sprintf("%v=%v", key, value))
+	})
+
+	// 插入足够多的键以触发扩容, 扩容时不应触发回调
+	for i := 0; i < 20; i++ {
+		table.Insert(i, i)
+	}
+	if table.ResizeCount() == 0 {
+		t.Fatalf("期望发生扩容")
+	}
+	if insertCalls != 20 {
+		t.Errorf("插入 20 个键期望回调 20 次, 实际为 %d", insertCalls)
+	}
+
+	// 更新已存在的键以新值触发回调
+	table.Insert(0, 100)
+	table.Swap(1, 101)
+	table.CompareAndSwap(2, 2, 102, nil)
+	table.CompareAndSwap(3, -1, 103, nil)
+	if insertCalls != 23 {
+		t.Errorf("更新后期望回调 23 次, 实际为 %d", insertCalls)
+	}
+	if inserted[0] != 100 || inserted[1] != 101 || inserted[2] != 102 || inserted[3] != 3 {
+		t.Errorf("更新回调应传入新值, 实际为 %v", inserted)
+	}
+
+	table.Delete(0)
+	table.Delete(0)
+	table.Delete("absent")
+	table.CompareAndDelete(5, 5, nil)
+	if deleteCalls != 2 {
+		t.Errorf("期望删除回调 2 次, 实际为 %d", deleteCalls)
+	}
+	if fmt.Sprint(deleted) != "[0=100 5=5]" {
+		t.Errorf("删除回调应传入被删除的键值, 实际为 %v", deleted)
+	}
+
+	table.OnInsert(nil)
+	table.OnDelete(nil)
+	table.Insert("x", 1)
+	table.Delete("x")
+	if insertCalls != 23 || deleteCalls != 2 {
+		t.Errorf("取消回调后不应再触发")
+	}
+}
+
+// 测试 Pop 方法
+func TestPop(t *testing.T) {
+	table := NewTable(8, WithAutoShrink(0.25))
+	for i := 0; i < 200; i++ {
+		table.Insert(i, i*10)
+	}
+
+	seen := make(map[any]int)
+	for {
+		key, value, ok := table.Pop()
+		if !ok {
+			break
+		}
+		seen[key]++
+		if value != key.(int)*10 {
+			t.Errorf("Pop 返回的值与键不匹配, key=%v, value=%v", key, value)
+		}
+		// 中途插入新键也应被弹出
+		if key == 100 {
+			table.Insert(1000, 10000)
+		}
+	}
+
+	if table.Size() != 0 {
+		t.Errorf("全部弹出后 size 期望为 0, 实际为 %d", table.Size())
+	}
+	if len(seen) != 201 {
+		t.Errorf("期望弹出 201 个不同的键, 实际为 %d", len(seen))
+	}
+	for k, n := range seen {
+		if n != 1 {
+			t.Errorf("键 %v 被弹出了 %d 次", k, n)
+		}
+	}
+
+	if _, _, ok := table.Pop(); ok {
+		t.Errorf("空表 Pop 应返回 ok=false")
+	}
+}
+
+// 测试 CapacityFor 的边界值
+func TestCapacityFor(t *testing.T) {
+	cases := []struct {
+		n          int
+		loadFactor float64
+		want       int
+	}{
+		{0, 0.75, 8},
+		{1, 0.75, 8},
+		{6, 0.75, 8},
+		{7, 0.75, 10},
+		{100, 0.5, 200},
+		{7, 0.7, 10},
+		{1000000, 0.75, 1333334},
+	}
+	for _, c := range cases {
+		got := CapacityFor(c.n, c.loadFactor)
+		if got != c.want {
+			t.Errorf("CapacityFor(%d, %v) 期望为 %d, 实际为 %d", c.n, c.loadFactor, c.want, got)
+		}
+		// 用该容量创建的表插入 n 个键不应扩容
+		if c.n <= 1000 {
+			table := NewTable(got)
+			_ = table.SetLoadFactor(c.loadFactor)
+			for i := 0; i < c.n; i++ {
+				table.Insert(i, i)
+			}
+			if table.ResizeCount() != 0 {
+				t.Errorf("CapacityFor(%d, %v) 容量不足, 插入时发生了扩容", c.n, c.loadFactor)
+			}
+		}
+	}
+
+	for _, lf := range []float64{0, 1, -0.5} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("loadFactor=%v 应 panic", lf)
+				}
+			}()
+			CapacityFor(10, lf)
+		}()
+	}
+}
+
+// 测试 Increment 计数
+func TestIncrement(t *testing.T) {
+	table := NewTable(8)
+
+	if v, err := table.Increment("hits", 1); err != nil || v != 1 {
+		t.Errorf("首次计数期望为 1, 实际为 %v, err=%v", v, err)
+	}
+	for i := 0; i < 9; i++ {
+		table.Increment("hits", 1)
+	}
+	if v, err := table.Increment("hits", -5); err != nil || v != 5 {
+		t.Errorf("多次计数后期望为 5, 实际为 %v, err=%v", v, err)
+	}
+	if v := table.Find("hits"); v != int64(5) {
+		t.Errorf("存储的值期望为 int64(5), 实际为 %#v", v)
+	}
+
+	// 其它整数类型会被转换
+	table.Insert("int", 10)
+	table.Insert("uint8", uint8(20))
+	if v, err := table.Increment("int", 1); err != nil || v != 11 {
+		t.Errorf("int 计数期望为 11, 实际为 %v, err=%v", v, err)
+	}
+	if v, err := table.Increment("uint8", 1); err != nil || v != 21 {
+		t.Errorf("uint8 计数期望为 21, 实际为 %v, err=%v", v, err)
+	}
+
+	// 非整数值返回错误且不修改表
+	table.Insert("name", "alice")
+	table.Insert("nil", nil)
+	for _, key := range []string{"name", "nil"} {
+		before := table.Find(key)
+		if _, err := table.Increment(key, 1); !errors.Is(err, ErrNotInteger) {
+			t.Errorf("%s 期望返回 ErrNotInteger, 实际为 %v", key, err)
+		}
+		if v := table.Find(key); v != before {
+			t.Errorf("%s 出错后值不应改变, 实际为 %v", key, v)
+		}
+	}
+}
+
+// 测试空表短路与 Contains
+func TestEmptyShortCircuit(t *testing.T) {
+	table := NewTable(8)
+
+	calls := 0
+	table.hashFn = func(key any) uint64 {
+		calls++
+		return uint64(len(fmt.Sprint(key)))
+	}
+
+	if table.Contains("a") || table.Find("a") != nil {
+		t.Errorf("空表中不应找到任何键")
+	}
+	if r := table.FindBatch([]any{"a", "b", "c"}); len(r) != 3 || r[0] != nil || r[1] != nil || r[2] != nil {
+		t.Errorf("空表批量查找期望返回 3 个 nil, 实际为 %v", r)
+	}
+	if calls != 0 {
+		t.Errorf("空表查找不应计算哈希, 实际调用 %d 次", calls)
+	}
+
+	table.Insert("a", 1)
+	table.Insert("b", nil)
+	if !table.Contains("a") || !table.Contains("b") || table.Contains("c") {
+		t.Errorf("Contains 结果错误")
+	}
+	if r := table.FindBatch([]any{"a", "b", "c"}); r[0] != 1 || r[1] != nil || r[2] != nil {
+		t.Errorf("批量查找结果错误, 实际为 %v", r)
+	}
+
+	table.Delete("a")
+	table.Delete("b")
+	if table.Contains("a") || table.Contains("b") {
+		t.Errorf("删除后不应再找到")
+	}
+}
@@ -0,0 +1,31 @@
+package table
+
+import "time"
+
+// WithTimestamps 记录每个键的插入时间和最近访问时间，用于 Age 和 IdleTime，时间来自 WithClock 设置的时钟
+// 插入、更新以及 Find、FindOr 等查找都会刷新访问时间，因此开启后查找也会修改表，不能与其它操作并发
+func WithTimestamps() Option {
+	return func(st *Table) {
+		st.timestamps = true
+	}
+}
+
+// Age 返回键自插入以来经过的时间，更新值不会重置插入时间
+// 键不存在或未开启 WithTimestamps 时第二个返回值为 false；查询不会刷新访问时间
+func (st *Table) Age(key any) (time.Duration, bool) {
+	slot := st.lookup(key)
+	if slot < 0 || !st.timestamps {
+		return 0, false
+	}
+	return time.Duration(st.now().UnixNano() - st.side.createdAt[slot]), true
+}
+
+// IdleTime 返回键自最近一次插入、更新或查找以来经过的时间
+// 键不存在或未开启 WithTimestamps 时第二个返回值为 false；查询不会刷新访问时间
+func (st *Table) IdleTime(key any) (time.Duration, bool) {
+	slot := st.lookup(key)
+	if slot < 0 || !st.timestamps {
+		return 0, false
+	}
+	return time.Duration(st.now().UnixNano() - st.side.accessedAt[slot]), true
+}
@@ -0,0 +1,62 @@
+package table
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTimestamps 使用假时钟测试 Age 与 IdleTime
+func TestTimestamps(t *testing.T) {
+	now := time.Unix(1000, 0)
+	table := NewTable(8, WithTimestamps(), WithClock(func() time.Time { return now }))
+
+	table.Insert("a", 1)
+	now = now.Add(5 * time.Second)
+
+	if age, ok := table.Age("a"); !ok || age != 5*time.Second {
+		t.Errorf("期望 Age=5s, 实际为 %v, %v", age, ok)
+	}
+	if idle, ok := table.IdleTime("a"); !ok || idle != 5*time.Second {
+		t.Errorf("期望 IdleTime=5s, 实际为 %v, %v", idle, ok)
+	}
+
+	// 查找刷新访问时间, 不影响插入时间
+	table.Find("a")
+	now = now.Add(2 * time.Second)
+	if age, _ := table.Age("a"); age != 7*time.Second {
+		t.Errorf("查找后期望 Age=7s, 实际为 %v", age)
+	}
+	if idle, _ := table.IdleTime("a"); idle != 2*time.Second {
+		t.Errorf("查找后期望 IdleTime=2s, 实际为 %v", idle)
+	}
+
+	// 更新值同样刷新访问时间
+	table.Insert("a", 2)
+	now = now.Add(time.Second)
+	if age, _ := table.Age("a"); age != 8*time.Second {
+		t.Errorf("更新后期望 Age=8s, 实际为 %v", age)
+	}
+	if idle, _ := table.IdleTime("a"); idle != time.Second {
+		t.Errorf("更新后期望 IdleTime=1s, 实际为 %v", idle)
+	}
+
+	// 快照不刷新访问时间, 原表的查找也不影响快照
+	snap := table.Snapshot()
+	table.Find("a")
+	snap.Find("a")
+	if idle, _ := snap.IdleTime("a"); idle != time.Second {
+		t.Errorf("快照期望 IdleTime=1s, 实际为 %v", idle)
+	}
+	if idle, _ := table.IdleTime("a"); idle != 0 {
+		t.Errorf("查找后期望 IdleTime=0, 实际为 %v", idle)
+	}
+
+	if _, ok := table.Age("missing"); ok {
+		t.Errorf("不存在的键应返回 false")
+	}
+	plain := NewTable(8)
+	plain.Insert("a", 1)
+	if _, ok := plain.Age("a"); ok {
+		t.Errorf("未开启 WithTimestamps 时应返回 false")
+	}
+}
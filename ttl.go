@@ -0,0 +1,82 @@
+package table
+
+import "time"
+
+// InsertWithTTL 插入或更新键值，并在 ttl 之后过期，ttl <= 0 表示永不过期
+//
+// 过期的键在查找时视为不存在，并在被访问时惰性删除；
+// 删除之前仍然计入 Size，可以调用 PurgeExpired 主动清理
+func (st *Table) InsertWithTTL(key any, value any, ttl time.Duration) {
+	if ttl <= 0 {
+		st.put(key, value, 0)
+		return
+	}
+
+	st.hasTTL = true
+	st.put(key, value, st.now().Add(ttl).UnixNano())
+}
+
+// PurgeExpired 删除所有已过期的键值对，返回删除的数量
+// 开启 WithWeakValues 时同时删除值已被回收的键值对
+func (st *Table) PurgeExpired() int {
+	if !st.hasTTL && !st.weakValues {
+		return 0
+	}
+
+	st.checkWritable()
+	now := st.expireNow()
+	purged := 0
+	for i := 0; i < st.capacity; i++ {
+		if st.metas[i]&0x03 == metaFull && !st.live(i, now) {
+			st.clearSlot(i)
+			purged++
+		}
+	}
+	st.maybeAutoShrink()
+	st.maybeAutoCompact()
+	return purged
+}
+
+// now 返回当前时间，优先使用 WithClock 设置的时钟
+func (st *Table) now() time.Time {
+	if st.clock != nil {
+		return st.clock()
+	}
+	return time.Now()
+}
+
+// expireNow 返回用于判断过期的当前时间，没有插入过带过期时间的键值对时返回 0，避免读取时钟
+func (st *Table) expireNow() int64 {
+	if !st.hasTTL {
+		return 0
+	}
+	return st.now().UnixNano()
+}
+
+// live 判断槽位是否存放着未过期的键值对，now 为 expireNow 的返回值
+// 开启 WithWeakValues 时值已被回收的键值对同样视为过期
+func (st *Table) live(i int, now int64) bool {
+	if st.metas[i]&0x03 != metaFull {
+		return false
+	}
+	if st.weakValues && st.reclaimed(i) {
+		return false
+	}
+	expireAt := st.expireAtSlot(i)
+	return expireAt == 0 || expireAt > now
+}
+
+// sizeAt 返回在 now 时刻未过期的键值对数量
+func (st *Table) sizeAt(now int64) int {
+	if !st.hasTTL && !st.weakValues {
+		return st.size
+	}
+
+	count := 0
+	for i := 0; i < st.capacity; i++ {
+		if st.live(i, now) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,170 @@
+package table
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakeClock 可以手动拨动的时钟
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.t }
+
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+// TestInsertWithTTL 测试键值对在过期后视为不存在
+func TestInsertWithTTL(t *testing.T) {
+	clock := newFakeClock()
+	table := NewTable(8, WithClock(clock.Now))
+
+	table.InsertWithTTL("short", 1, time.Second)
+	table.InsertWithTTL("long", 2, time.Minute)
+	table.Insert("forever", 3)
+	table.InsertWithTTL("zero", 4, 0)
+
+	clock.Advance(999 * time.Millisecond)
+	if v := table.Find("short"); v != 1 {
+		t.Errorf("未过期时期望找到 short, 返回 %v", v)
+	}
+
+	clock.Advance(time.Millisecond)
+	if v := table.Find("short"); v != nil {
+		t.Errorf("过期后期望找不到 short, 返回 %v", v)
+	}
+	if table.Size() != 3 {
+		t.Errorf("过期的键被访问后应被删除, 期望 size=3, 实际为 %d", table.Size())
+	}
+
+	clock.Advance(time.Hour)
+	if table.Contains("long") {
+		t.Errorf("long 应已过期")
+	}
+	if table.Find("forever") != 3 || table.Find("zero") != 4 {
+		t.Errorf("没有过期时间的键不应过期")
+	}
+	if table.Size() != 2 {
+		t.Errorf("期望 size=2, 实际为 %d", table.Size())
+	}
+}
+
+// TestTTLReinsert 测试重新插入会刷新或清除过期时间
+func TestTTLReinsert(t *testing.T) {
+	clock := newFakeClock()
+	table := NewTable(8, WithClock(clock.Now))
+
+	table.InsertWithTTL("k", 1, time.Second)
+	clock.Advance(2 * time.Second)
+
+	// 过期但尚未清理的键视为不存在
+	previous, loaded := table.Swap("k", 2)
+	if previous != nil || loaded {
+		t.Errorf("覆盖已过期的键期望返回 nil, false, 实际为 %v, %v", previous, loaded)
+	}
+	clock.Advance(time.Hour)
+	if v := table.Find("k"); v != 2 {
+		t.Errorf("不带过期时间重新插入后不应过期, 返回 %v", v)
+	}
+
+	table.InsertWithTTL("k", 3, time.Second)
+	table.InsertWithTTL("k", 4, time.Minute)
+	clock.Advance(time.Second)
+	if v := table.Find("k"); v != 4 {
+		t.Errorf("刷新过期时间后不应过期, 返回 %v", v)
+	}
+}
+
+// TestTTLUpdateKeepsExpiry 测试 Increment、Upsert 与 ReplaceIfPresent 更新已存在的键时保留过期时间
+func TestTTLUpdateKeepsExpiry(t *testing.T) {
+	clock := newFakeClock()
+	table := NewTable(8, WithClock(clock.Now))
+
+	table.InsertWithTTL("counter", 1, time.Second)
+	table.InsertWithTTL("upsert", 1, time.Second)
+	table.InsertWithTTL("replace", 1, time.Second)
+	if n, err := table.Increment("counter", 2); err != nil || n != 3 {
+		t.Fatalf("Increment 期望返回 3, 实际为 %d, %v", n, err)
+	}
+	table.Upsert("upsert", 2, func(existing, incoming any) any { return existing.(int) + incoming.(int) })
+	table.ReplaceIfPresent("replace", 2)
+
+	clock.Advance(time.Second)
+	for _, k := range []string{"counter", "upsert", "replace"} {
+		if table.Contains(k) {
+			t.Errorf("%s 更新后应保留原来的过期时间", k)
+		}
+	}
+
+	// 过期后再计数视为新键, 不带过期时间
+	if n, _ := table.Increment("counter", 5); n != 5 {
+		t.Errorf("过期后 Increment 期望从 0 开始, 实际为 %d", n)
+	}
+	clock.Advance(time.Hour)
+	if v := table.Find("counter"); v != int64(5) {
+		t.Errorf("不带过期时间的计数不应过期, 返回 %v", v)
+	}
+}
+
+// TestTTLIteration 测试遍历类方法跳过已过期的键
+func TestTTLIteration(t *testing.T) {
+	clock := newFakeClock()
+	table := NewTable(8, WithClock(clock.Now))
+	for i := 0; i < 10; i++ {
+		table.InsertWithTTL(fmt.Sprintf("ttl-%d", i), i, time.Second)
+		table.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+
+	clock.Advance(time.Second)
+	if n := len(table.Keys()); n != 10 {
+		t.Errorf("Keys 不应包含过期的键, 期望 10 个, 实际为 %d", n)
+	}
+	if n := len(table.ToMap()); n != 10 {
+		t.Errorf("ToMap 不应包含过期的键, 期望 10 个, 实际为 %d", n)
+	}
+	if n := table.CountFunc(func(k, v any) bool { return true }); n != 10 {
+		t.Errorf("CountFunc 不应计入过期的键, 实际为 %d", n)
+	}
+
+	other := NewTable(8)
+	for i := 0; i < 10; i++ {
+		other.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	if !table.Equal(other, nil) {
+		t.Errorf("忽略过期的键后两个表应相等")
+	}
+
+	if n := table.PurgeExpired(); n != 10 {
+		t.Errorf("PurgeExpired 期望删除 10 个, 实际为 %d", n)
+	}
+	if table.Size() != 10 {
+		t.Errorf("清理后期望 size=10, 实际为 %d", table.Size())
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("清理后校验失败: %v", err)
+	}
+}
+
+// TestPurgeExpiredAutoShrink 测试开启自动缩容时清理过期键不会遗漏
+func TestPurgeExpiredAutoShrink(t *testing.T) {
+	clock := newFakeClock()
+	table := NewTable(8, WithClock(clock.Now), WithAutoShrink(0.25))
+	for i := 0; i < 1000; i++ {
+		table.InsertWithTTL(i, i, time.Second)
+	}
+	table.Insert("keep", 1)
+	peak := table.Capacity()
+
+	clock.Advance(time.Second)
+	if n := table.PurgeExpired(); n != 1000 {
+		t.Errorf("期望清理 1000 个, 实际为 %d", n)
+	}
+	if table.Size() != 1 || table.Capacity() >= peak {
+		t.Errorf("清理后期望 size=1 且容量缩小, size=%d, 容量=%d", table.Size(), table.Capacity())
+	}
+}
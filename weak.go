@@ -0,0 +1,34 @@
+package table
+
+// WithWeakValues 以弱引用保存指针类型的值，值只被表引用时可以被 GC 回收
+// 值被回收后对应的键视为不存在，与过期的键一样在被访问时惰性删除，删除之前仍然计入 Size，
+// 可以调用 PurgeExpired 主动清理；非指针类型的值（如 int、string、切片）照常保存。
+// 弱引用依赖 Go 1.24 的 weak 包，更早的版本下该选项不生效，所有值都是强引用
+func WithWeakValues() Option {
+	return func(st *Table) {
+		st.weakValues = true
+	}
+}
+
+// wrapValue 返回写入槽位的值，开启 WithWeakValues 时把指针值换成弱引用
+func (st *Table) wrapValue(v any) any {
+	if !st.weakValues {
+		return v
+	}
+	return makeWeak(v)
+}
+
+// valueAt 返回槽位中的值，弱引用的值已被回收时返回 nil
+func (st *Table) valueAt(i int) any {
+	if !st.weakValues {
+		return st.entries[i].value
+	}
+	v, _ := resolveWeak(st.entries[i].value)
+	return v
+}
+
+// reclaimed 判断槽位中弱引用的值是否已被回收
+func (st *Table) reclaimed(i int) bool {
+	_, ok := resolveWeak(st.entries[i].value)
+	return !ok
+}
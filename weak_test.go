@@ -0,0 +1,77 @@
+//go:build go1.24
+
+package table
+
+import (
+	"runtime"
+	"testing"
+)
+
+type payload struct {
+	data [1 << 16]byte
+	name string
+}
+
+// TestWeakValues 测试值只被表引用时 GC 后键值对消失
+func TestWeakValues(t *testing.T) {
+	table := NewTable(8, WithWeakValues())
+
+	kept := &payload{name: "kept"}
+	table.Insert("kept", kept)
+	table.Insert("dropped", &payload{name: "dropped"})
+	table.Insert("plain", 42)
+
+	if v, ok := table.Find("dropped").(*payload); !ok || v.name != "dropped" {
+		t.Fatalf("回收前期望找到 dropped, 实际为 %v", v)
+	}
+
+	runtime.GC()
+	runtime.GC()
+
+	if v := table.Find("dropped"); v != nil {
+		t.Errorf("GC 后 dropped 期望被回收, 实际为 %v", v)
+	}
+	if table.Contains("dropped") || table.Size() != 2 {
+		t.Errorf("访问后已回收的键应被删除, size=%d", table.Size())
+	}
+
+	// 仍被引用的指针和非指针值不受影响
+	if v := table.Find("kept"); v != kept {
+		t.Errorf("kept 期望为原指针, 实际为 %v", v)
+	}
+	if v := table.Find("plain"); v != 42 {
+		t.Errorf("plain 期望为 42, 实际为 %v", v)
+	}
+
+	// 遍历时跳过已回收的值, PurgeExpired 主动清理
+	table.Insert("dropped-2", &payload{name: "dropped-2"})
+	runtime.GC()
+	runtime.GC()
+	table.Range(func(key, value any) bool {
+		if value == nil {
+			t.Errorf("遍历时不应出现已回收的值, key=%v", key)
+		}
+		return true
+	})
+	if n := table.PurgeExpired(); n != 1 {
+		t.Errorf("期望清理 1 个已回收的键, 实际为 %d", n)
+	}
+	if table.Size() != 2 {
+		t.Errorf("清理后期望 size=2, 实际为 %d", table.Size())
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("校验失败: %v", err)
+	}
+	runtime.KeepAlive(kept)
+}
+
+// TestWeakValuesDisabled 测试默认强引用保存值
+func TestWeakValuesDisabled(t *testing.T) {
+	table := NewTable(8)
+	table.Insert("a", &payload{name: "a"})
+	runtime.GC()
+	runtime.GC()
+	if v, ok := table.Find("a").(*payload); !ok || v.name != "a" {
+		t.Errorf("未开启弱引用时值不应被回收, 实际为 %v", v)
+	}
+}
@@ -0,0 +1,38 @@
+//go:build go1.24
+
+package table
+
+import (
+	"reflect"
+	"unsafe"
+	"weak"
+)
+
+// weakValue 是开启 WithWeakValues 后指针值在槽位中的存储形式，只持有值的弱引用
+type weakValue struct {
+	ptr weak.Pointer[byte]
+	typ reflect.Type
+}
+
+// makeWeak 把非 nil 的指针包装为弱引用，其它值原样返回
+func makeWeak(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return v
+	}
+	return weakValue{ptr: weak.Make((*byte)(rv.UnsafePointer())), typ: rv.Type()}
+}
+
+// resolveWeak 还原弱引用指向的值，值已被回收时第二个返回值为 false
+func resolveWeak(v any) (any, bool) {
+	w, ok := v.(weakValue)
+	if !ok {
+		return v, true
+	}
+
+	p := w.ptr.Value()
+	if p == nil {
+		return nil, false
+	}
+	return reflect.NewAt(w.typ.Elem(), unsafe.Pointer(p)).Convert(w.typ).Interface(), true
+}
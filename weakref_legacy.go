@@ -0,0 +1,13 @@
+//go:build !go1.24
+
+package table
+
+// makeWeak Go 1.24 之前没有 weak 包，值原样保存
+func makeWeak(v any) any {
+	return v
+}
+
+// resolveWeak Go 1.24 之前值总是强引用，不会被回收
+func resolveWeak(v any) (any, bool) {
+	return v, true
+}